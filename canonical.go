@@ -0,0 +1,236 @@
+package jsonutils
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonicalize returns the canonical form of the JSON document b, as defined
+// by the JSON Canonicalization Scheme (JCS, RFC 8785), which is suitable for
+// hashing and signing. In the canonical form:
+//	- There is no whitespace between tokens.
+//	- Object members are sorted by their names, compared as UTF-16 code
+//		units.
+//	- Numbers are formatted like ECMAScript does, after being converted to
+//		IEEE 754 double precision.
+//	- Strings only escape the characters that JSON requires, using the short
+//		forms when available.
+//
+// As required by the RFC, b must also follow I-JSON (RFC 7493). It returns a
+// *SyntaxError if b is not valid JSON, an error matching ErrDuplicateKey if an
+// Object has duplicate member names, a *SyntaxError wrapping ErrInvalidString
+// if a String is not valid Unicode, and a *NumberError if a Number doesn't
+// fit in a double precision float.
+func Canonicalize(b []byte) ([]byte, error) {
+	if _, err := TypeOfStrict(b); err != nil {
+		return nil, err
+	}
+	start := skipSpace(b, 0)
+	return appendCanonical(make([]byte, 0, len(b)), b, start,
+		start+len(trimSpace(b)))
+}
+
+// CanonicalizeValue returns the canonical form of the JSON encoding of v, as
+// returned by encoding/json. See Canonicalize for details.
+func CanonicalizeValue(v interface{}) ([]byte, error) {
+	b, err := encodeTree(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(b)
+}
+
+// appendCanonical appends to buf the canonical form of the valid JSON value in
+// b[start:end].
+func appendCanonical(buf, b []byte, start, end int) ([]byte, error) {
+	var err error
+	switch c := b[start]; {
+	case c == '{':
+		var members []member
+		var offsets []int
+		eachMember(b[:end], start, 1, func(ks, ke, vs, ve int) bool {
+			name, _ := Unquote(b[ks:ke])
+			members = append(members, member{name: name, rawName: b[ks:ke],
+				value: b[vs:ve]})
+			offsets = append(offsets, ks, vs, ve)
+			return true
+		})
+
+		order := make([]int, len(members))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool {
+			return lessUTF16(members[order[i]].name, members[order[j]].name)
+		})
+
+		buf = append(buf, '{')
+		for i, m := range order {
+			ks, vs, ve := offsets[3*m], offsets[3*m+1], offsets[3*m+2]
+			if i > 0 {
+				buf = append(buf, ',')
+				if members[order[i-1]].name == members[m].name {
+					return nil, newSyntaxError(b, ks, ErrDuplicateKey)
+				}
+			}
+			if !isValidUnicode(members[m].rawName) {
+				return nil, newSyntaxError(b, ks, ErrInvalidString)
+			}
+			buf = appendCanonicalString(buf, members[m].name)
+			buf = append(buf, ':')
+			if buf, err = appendCanonical(buf, b, vs, ve); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+
+	case c == '[':
+		buf = append(buf, '[')
+		n := 0
+		eachElement(b[:end], start, 1, func(s, e int) bool {
+			if n > 0 {
+				buf = append(buf, ',')
+			}
+			n++
+			buf, err = appendCanonical(buf, b, s, e)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, ']'), nil
+
+	case c == '"':
+		if !isValidUnicode(b[start:end]) {
+			return nil, newSyntaxError(b, start, ErrInvalidString)
+		}
+		s, _ := Unquote(b[start:end])
+		return appendCanonicalString(buf, s), nil
+
+	case c == '-', '0' <= c && c <= '9':
+		s := bytesToString(b[start:end])
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, newNumberError(string(b[start:end]), GoFloat, start,
+				err)
+		}
+		return appendES6Number(buf, f), nil
+	}
+
+	// Literals are already canonical.
+	return append(buf, b[start:end]...), nil
+}
+
+// lessUTF16 reports whether a sorts before b when compared as UTF-16 code
+// units.
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			a1, a2 := utf16Units(ra)
+			b1, b2 := utf16Units(rb)
+			if a1 != b1 {
+				return a1 < b1
+			}
+			return a2 < b2
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return a == "" && b != ""
+}
+
+// utf16Units returns the UTF-16 code units of r. The second one is zero if r
+// is in the Basic Multilingual Plane.
+func utf16Units(r rune) (rune, rune) {
+	if r < 0x10000 {
+		return r, 0
+	}
+	return utf16.EncodeRune(r)
+}
+
+// isValidUnicode reports whether the JSON String raw, which must be valid, is
+// valid UTF-8 and has no unpaired UTF-16 surrogates in its escapes.
+func isValidUnicode(raw []byte) bool {
+	if !utf8.Valid(raw) {
+		return false
+	}
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			continue
+		}
+		if raw[i+1] != 'u' {
+			i++
+			continue
+		}
+		r := getu4(raw[i:])
+		i += 5
+		switch {
+		case 0xD800 <= r && r < 0xDC00:
+			if lo := getu4(raw[i+1:]); lo < 0xDC00 || lo > 0xDFFF {
+				return false
+			}
+			i += 6
+		case 0xDC00 <= r && r <= 0xDFFF:
+			return false
+		}
+	}
+	return true
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendCanonicalString appends s as a JSON String, escaping only what JSON
+// requires, like ECMAScript's JSON.stringify does.
+func appendCanonicalString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"', c == '\\':
+			buf = append(buf, '\\', c)
+		case c == '\b':
+			buf = append(buf, '\\', 'b')
+		case c == '\f':
+			buf = append(buf, '\\', 'f')
+		case c == '\n':
+			buf = append(buf, '\\', 'n')
+		case c == '\r':
+			buf = append(buf, '\\', 'r')
+		case c == '\t':
+			buf = append(buf, '\\', 't')
+		case c < ' ':
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4],
+				hexDigits[c&0xF])
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
+
+// appendES6Number appends f formatted like ECMAScript's Number.toString does,
+// which is also what encoding/json does for float64 values.
+func appendES6Number(buf []byte, f float64) []byte {
+	if f == 0 {
+		// Also for negative zero.
+		return append(buf, '0')
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf
+}
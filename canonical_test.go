@@ -0,0 +1,171 @@
+package jsonutils
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Want  string
+		Error error
+	}{
+
+		{
+			// RFC 8785 section 3.2.2.
+			Name: "RFC 8785 example",
+			Input: `{
+				"numbers": [333333333.33333329, 1E30, 4.50,
+					2e-3, 0.000000000000000000000000001],
+				"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			Want: `{"literals":[null,true,false],"numbers":[333333333.3333333,` +
+				`1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		}, //*/
+
+		{
+			// RFC 8785 section 3.2.3.
+			Name: "RFC 8785 sorting",
+			Input: `{
+				"\u20ac": "Euro Sign",
+				"\r": "Carriage Return",
+				"\ufb33": "Hebrew Letter Dalet With Dagesh",
+				"1": "One",
+				"\ud83d\ude00": "Emoji: Grinning Face",
+				"\u0080": "Control",
+				"\u00f6": "Latin Small Letter O With Diaeresis"
+			}`,
+			Want: "{\"\\r\":\"Carriage Return\",\"1\":\"One\"," +
+				"\"\u0080\":\"Control\"," +
+				"\"ö\":\"Latin Small Letter O With Diaeresis\"," +
+				"\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\"," +
+				"\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		}, //*/
+
+		{
+			Name:  "No HTML escaping",
+			Input: `"<\u2028&>"`,
+			Want:  "\"<\u2028&>\"",
+		}, //*/
+
+		{
+			Name:  "Nested",
+			Input: ` [ {"b": [], "a": {}} , -0 , 1.0 ] `,
+			Want:  `[{"a":{},"b":[]},0,1]`,
+		}, //*/
+
+		{
+			Name:  "Duplicate key",
+			Input: `{"a":1,"b":2,"\u0061":3}`,
+			Error: ErrDuplicateKey,
+		}, //*/
+
+		{
+			Name:  "Unpaired surrogate",
+			Input: `["\ud83d"]`,
+			Error: ErrInvalidString,
+		}, //*/
+
+		{
+			Name:  "Unpaired surrogate in key",
+			Input: `{"\ude00":1}`,
+			Error: ErrInvalidString,
+		}, //*/
+
+		{
+			Name:  "Invalid UTF-8",
+			Input: "\"\xff\"",
+			Error: ErrInvalidString,
+		}, //*/
+
+		{
+			Name:  "Invalid JSON",
+			Input: `{"a":1,}`,
+			Error: ErrInvalidJSON,
+		}, //*/
+	}
+
+	for _, test := range tests {
+		have, err := Canonicalize([]byte(test.Input))
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Name, test.Error,
+				err)
+		}
+		if string(have) != test.Want {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.Name, test.Want, have)
+		}
+	}
+
+	// Numbers must fit in a double precision float.
+	var numErr *NumberError
+	_, err := Canonicalize([]byte(`[1e400]`))
+	if !errors.As(err, &numErr) || numErr.Offset != 1 {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestCanonicalizeValue(t *testing.T) {
+	v := map[string]interface{}{
+		"z": []interface{}{1.5, "<>", nil},
+		"a": struct {
+			B bool `json:"b"`
+			A int  `json:"a"`
+		}{true, 1e6},
+	}
+	want := `{"a":{"a":1000000,"b":true},"z":[1.5,"<>",null]}`
+	have, err := CanonicalizeValue(v)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(have) != want {
+		t.Fatalf("Want: %s; Have: %s", want, have)
+	}
+
+	if _, err = CanonicalizeValue(math.NaN()); err == nil {
+		t.Fatalf("Want an error")
+	}
+}
+
+func TestAppendES6Number(t *testing.T) {
+	// RFC 8785 appendix B.
+	tests := []struct {
+		Bits uint64
+		Want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, test := range tests {
+		f := math.Float64frombits(test.Bits)
+		if have := string(appendES6Number(nil, f)); have != test.Want {
+			t.Fatalf("[%#016x] Want: %s; Have: %s", test.Bits, test.Want, have)
+		}
+	}
+}
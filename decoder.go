@@ -0,0 +1,194 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DecodeError is returned by Decoder when a value could not be read from the
+// input, or it was rejected by the Payload.
+type DecodeError struct {
+	// Offset is the input offset where the value that failed to decode
+	// starts, or where reading the input failed.
+	Offset int64
+	// Err is the underlying error, like a *json.SyntaxError or an
+	// *UnexpectedTypeError.
+	Err error
+}
+
+// Error returns the underlying error message along with the input offset.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding value at input offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// Decoder reads a stream of JSON values from an io.Reader and decodes them one
+// at a time, each of them with the JSON Data Type dispatch of Payload.
+//
+// By default, each top-level value of the stream is decoded. With
+// WithArrayStream, each element of top-level JSON Arrays is decoded instead,
+// which allows processing huge JSON Arrays in constant memory.
+//
+// Decoder is not safe to use in concurrent goroutines.
+type Decoder struct {
+	dec jsonDecoder
+
+	// Reused buffer holding the last value read
+	raw json.RawMessage
+
+	arrayStream bool
+	inArray     bool // Already read the opening bracket of a JSON Array
+
+	// Error found by More, returned by the next call to a Decode* method
+	err error
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// WithArrayStream configures the Decoder to decode each element of the
+// top-level JSON Arrays of the stream, instead of the JSON Arrays themselves
+// (disabled by default). In this mode, top-level values other than JSON Arrays
+// return an *UnexpectedTypeError.
+//
+// The default behavior when calling this method is to enable this
+// configuration. It must not be changed after decoding started.
+func (d *Decoder) WithArrayStream(enable ...bool) *Decoder {
+	d.arrayStream = len(enable) == 0 || enable[0]
+	return d
+}
+
+// InputOffset returns the input stream byte offset of the current decoder
+// position.
+func (d *Decoder) InputOffset() int64 { return d.dec.InputOffset() }
+
+// More reports whether there is another value to decode. In array stream
+// mode, it skips the brackets and empty JSON Arrays before the next value, so
+// it only returns false at the end of the input. If the input is invalid, then
+// it returns true and the error is returned by the next call to a Decode*
+// method.
+func (d *Decoder) More() bool {
+	if !d.arrayStream {
+		return d.dec.More()
+	}
+	if d.err == nil {
+		d.err = d.next()
+	}
+	return d.err != io.EOF
+}
+
+// Decode decodes the next value into p, which should be configured to accept
+// it. It returns io.EOF when there are no more values, and a *DecodeError
+// otherwise.
+func (d *Decoder) Decode(p *Payload) error {
+	if _, _, err := d.DecodeRaw(); err != nil {
+		return err
+	}
+	if err := p.UnmarshalJSON(d.raw); err != nil {
+		return &DecodeError{Offset: d.valueOffset(), Err: err}
+	}
+	return nil
+}
+
+// DecodeRaw reads the next value, returning its bytes and its JSON Data Type.
+// The bytes are only valid until the next call to a Decode* method. It returns
+// io.EOF when there are no more values, and a *DecodeError otherwise.
+func (d *Decoder) DecodeRaw() (json.RawMessage, JSONType, error) {
+	if err := d.next(); err != nil {
+		return nil, InvalidJSON, err
+	}
+
+	if err := d.dec.Decode(&d.raw); err != nil {
+		return nil, InvalidJSON, d.readError(err)
+	}
+
+	t, err := TypeOf(d.raw)
+	if err != nil {
+		return nil, t, &DecodeError{Offset: d.valueOffset(), Err: err}
+	}
+	return d.raw, t, nil
+}
+
+// next positions the decoder right before the next value to decode.
+func (d *Decoder) next() error {
+	if !d.arrayStream {
+		return nil
+	}
+	if err := d.err; err != nil {
+		d.err = nil
+		return err
+	}
+
+	for {
+		if d.inArray {
+			if d.dec.More() {
+				return nil
+			}
+			// Consume the closing bracket.
+			if _, err := d.dec.Token(); err != nil {
+				return d.readError(err)
+			}
+			d.inArray = false
+		}
+
+		offset := d.dec.InputOffset()
+		tok, err := d.dec.Token()
+		if err == io.EOF {
+			return err
+		}
+		if err != nil {
+			return d.readError(err)
+		}
+		if tok != json.Delim('[') {
+			return &DecodeError{Offset: offset, Err: &UnexpectedTypeError{
+				Got: tokenType(tok), Allowed: []JSONType{Array}}}
+		}
+		d.inArray = true
+	}
+}
+
+// valueOffset returns the input offset where the last value read starts.
+func (d *Decoder) valueOffset() int64 {
+	return d.dec.InputOffset() - int64(len(d.raw))
+}
+
+// readError wraps an error returned while reading the input. io.EOF is
+// returned as is when it happens between top-level values.
+func (d *Decoder) readError(err error) error {
+	if err == io.EOF && !d.inArray {
+		return err
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	offset := d.dec.InputOffset()
+	if serr, ok := err.(*json.SyntaxError); ok {
+		offset = serr.Offset
+	}
+	return &DecodeError{Offset: offset, Err: err}
+}
+
+// tokenType returns the JSON Data Type of a json.Token that starts a value.
+func tokenType(tok json.Token) JSONType {
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			return Object
+		}
+		return Array
+	case nil:
+		return Null
+	case string:
+		return String
+	case float64, json.Number:
+		return Number
+	case bool:
+		return Boolean
+	}
+	return InvalidJSON
+}
@@ -0,0 +1,213 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	in := ` "a" 1 {"key":"K"} [1,2] null `
+	dec := NewDecoder(strings.NewReader(in))
+
+	p := AcquirePayload().WithString().WithInt().WithObject().WithArray().
+		WithNull()
+	defer ReleasePayload(p)
+
+	want := []GoMapping{GoString, GoInt, GoOther, GoOther, GoNil}
+	var have []GoMapping
+	for dec.More() {
+		if err := dec.Decode(p); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		have = append(have, p.GetMapping())
+	}
+	if len(have) != len(want) {
+		t.Fatalf("Want: %v; Have: %v", want, have)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Fatalf("Want: %v; Have: %v", want, have)
+		}
+	}
+	if err := dec.Decode(p); err != io.EOF {
+		t.Fatalf("Want: %v; Have: %v", io.EOF, err)
+	}
+}
+
+func TestDecoder_ArrayStream(t *testing.T) {
+	in := `[1, "two", {"three":3}] [] [null]`
+	dec := NewDecoder(strings.NewReader(in)).WithArrayStream()
+
+	want := []JSONType{Number, String, Object, Null}
+	var have []JSONType
+	for {
+		raw, jType, err := dec.DecodeRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(raw) == 0 {
+			t.Fatalf("Empty value")
+		}
+		have = append(have, jType)
+	}
+	if len(have) != len(want) {
+		t.Fatalf("Want: %v; Have: %v", want, have)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Fatalf("Want: %v; Have: %v", want, have)
+		}
+	}
+}
+
+func TestDecoder_ArrayStreamMore(t *testing.T) {
+	p := AcquirePayload().WithInt()
+	defer ReleasePayload(p)
+
+	for in, want := range map[string]int{
+		`[]`:          0,
+		`[1] []`:      1,
+		`[] [1]`:      1,
+		` [ ] [1, 2]`: 2,
+	} {
+		dec := NewDecoder(strings.NewReader(in)).WithArrayStream()
+		n := 0
+		for dec.More() {
+			if err := dec.Decode(p); err != nil {
+				t.Fatalf("[%s] Unexpected error: %v", in, err)
+			}
+			n++
+		}
+		if n != want {
+			t.Fatalf("[%s] Want: %d values; Have: %d", in, want, n)
+		}
+		if err := dec.Decode(p); err != io.EOF {
+			t.Fatalf("[%s] Want: %v; Have: %v", in, io.EOF, err)
+		}
+	}
+
+	// Errors found by More are returned by Decode.
+	dec := NewDecoder(strings.NewReader(`[1] 2`)).WithArrayStream()
+	var err error
+	for err == nil && dec.More() {
+		err = dec.Decode(p)
+	}
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) || decErr.Offset != 3 ||
+		!errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	var decErr *DecodeError
+
+	// The Payload rejects the value.
+	dec := NewDecoder(strings.NewReader(`1 "two"`))
+	p := AcquirePayload().WithInt()
+	defer ReleasePayload(p)
+	if err := dec.Decode(p); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err := dec.Decode(p)
+	if !errors.As(err, &decErr) || decErr.Offset != 2 ||
+		!errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Not an Array in array stream mode.
+	dec = NewDecoder(strings.NewReader(` {"key":"K"}`)).WithArrayStream()
+	err = dec.Decode(p)
+	if !errors.As(err, &decErr) || decErr.Offset != 0 ||
+		!errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Truncated Array.
+	dec = NewDecoder(strings.NewReader(`[1, 2`)).WithArrayStream()
+	for err = nil; err == nil; err = dec.Decode(p) {
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &decErr) || !errors.As(err, &syntaxErr) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Syntax error.
+	dec = NewDecoder(strings.NewReader(`[1, }`)).WithArrayStream()
+	for err = nil; err == nil; err = dec.Decode(p) {
+	}
+	if !errors.As(err, &decErr) || !errors.As(err, &syntaxErr) ||
+		decErr.Offset != syntaxErr.Offset {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// arrayReader generates a JSON Array of n Numbers on the fly.
+type arrayReader struct {
+	n, i int
+	buf  []byte
+}
+
+func (r *arrayReader) Read(b []byte) (int, error) {
+	for len(r.buf) < len(b) && r.i <= r.n {
+		switch {
+		case r.i == 0:
+			r.buf = append(r.buf, '[')
+		case r.i == r.n:
+			r.buf = append(r.buf, ']')
+		default:
+			if r.i > 1 {
+				r.buf = append(r.buf, ',')
+			}
+			r.buf = strconv.AppendInt(r.buf, int64(r.i), 10)
+		}
+		r.i++
+	}
+	if len(r.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.buf)
+	r.buf = r.buf[:copy(r.buf, r.buf[n:])]
+	return n, nil
+}
+
+func TestDecoder_LargeArray(t *testing.T) {
+	const n = 100000
+	dec := NewDecoder(&arrayReader{n: n}).WithArrayStream()
+	p := AcquirePayload().WithInt()
+	defer ReleasePayload(p)
+
+	var count int64
+	for dec.More() {
+		if err := dec.Decode(p); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		count++
+		if p.GetInt() != count {
+			t.Fatalf("Want: %d; Have: %d", count, p.GetInt())
+		}
+	}
+	if count != n-1 {
+		t.Fatalf("Want: %d; Have: %d", n-1, count)
+	}
+}
+
+func BenchmarkDecoder_ArrayStream(b *testing.B) {
+	p := AcquirePayload().WithInt()
+	defer ReleasePayload(p)
+
+	b.ReportAllocs()
+	dec := NewDecoder(&arrayReader{n: b.N + 1}).WithArrayStream()
+	for i := 0; i < b.N; i++ {
+		if err := dec.Decode(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Discriminator decodes JSON Objects whose concrete Go type depends on the
+// value of one of their members, like "type" or "kind". Each possible value of
+// that member is mapped to the PayloadFactory that creates the Go value to
+// decode into.
+//
+// The discriminator member is found by scanning the JSON Object, without
+// decoding it, and then the whole JSON Object is decoded straight into the
+// value created by the matching PayloadFactory.
+//
+// Typically, you would do something like this:
+//
+//	var d = NewDiscriminator("type").
+//		Register("user", func() interface{} { return new(User) }).
+//		Register("group", func() interface{} { return new(Group) })
+//
+// A Discriminator must be fully configured before being used. After that, it's
+// safe to use in concurrent goroutines, and it can be shared by many Payload
+// and PayloadSpec objects (see WithDiscriminator).
+type Discriminator struct {
+	key      string
+	types    map[string]PayloadFactory
+	fallback PayloadFactory
+}
+
+// NewDiscriminator returns a new Discriminator that looks for the member named
+// key.
+func NewDiscriminator(key string) *Discriminator {
+	return &Discriminator{
+		key:   key,
+		types: map[string]PayloadFactory{},
+	}
+}
+
+// Key returns the name of the discriminator member.
+func (d *Discriminator) Key() string { return d.key }
+
+// Register maps the discriminator value to the PayloadFactory f, replacing any
+// previous mapping for that value. Passing a nil f removes the mapping.
+func (d *Discriminator) Register(value string, f PayloadFactory) *Discriminator {
+	if f == nil {
+		delete(d.types, value)
+	} else {
+		d.types[value] = f
+	}
+	return d
+}
+
+// WithFallback configures the PayloadFactory used when the discriminator
+// member is missing or its value was not registered. Passing nil removes the
+// fallback, which is the default, so those cases return an error.
+func (d *Discriminator) WithFallback(f PayloadFactory) *Discriminator {
+	d.fallback = f
+	return d
+}
+
+// Decode decodes the JSON Object in b into a new value created by the
+// PayloadFactory matching its discriminator member, and returns it. A JSON Null
+// decodes to nil.
+//
+// Besides the errors returned by TypeOf and encoding/json, it returns:
+//	- An *UnexpectedTypeError if b is not a JSON Object or Null, or if the
+//		value of the discriminator member is not a JSON String.
+//	- An error matching ErrNoDiscriminator if the discriminator member is
+//		missing and there is no fallback.
+//	- An error matching ErrUnknownDiscriminator if the value of the
+//		discriminator member was not registered and there is no fallback.
+func (d *Discriminator) Decode(b []byte) (interface{}, error) {
+	t, start, end, err := d.bounds(b)
+	switch {
+	case err != nil, t == Null:
+		return nil, err
+	case t != Object:
+		return nil, &UnexpectedTypeError{Got: t,
+			Allowed: []JSONType{Object, Null}, Offset: int64(start)}
+	}
+	return d.decode(b, start, end)
+}
+
+// DecodeArray decodes each element of the JSON Array in b like Decode does,
+// and returns them in order. JSON Null elements decode to nil, and a JSON Null
+// in b returns a nil slice.
+//
+// It returns the same errors as Decode, and an *UnexpectedTypeError if b is not
+// a JSON Array or Null.
+func (d *Discriminator) DecodeArray(b []byte) ([]interface{}, error) {
+	t, start, end, err := d.bounds(b)
+	switch {
+	case err != nil, t == Null:
+		return nil, err
+	case t != Array:
+		return nil, &UnexpectedTypeError{Got: t,
+			Allowed: []JSONType{Array, Null}, Offset: int64(start)}
+	}
+
+	ret := []interface{}{}
+	var elemErr error
+	i, err := eachElement(b[:end], start, 1, func(s, e int) bool {
+		var v interface{}
+		switch b[s] {
+		case 'n':
+		case '{':
+			v, elemErr = d.decode(b, s, e)
+		default:
+			t, _ := TypeOf(b[s:e])
+			elemErr = &UnexpectedTypeError{Got: t,
+				Allowed: []JSONType{Object, Null}, Offset: int64(s)}
+		}
+		ret = append(ret, v)
+		return elemErr == nil
+	})
+	switch {
+	case elemErr != nil:
+		return nil, elemErr
+	case err != nil:
+		return nil, err
+	case i != end:
+		return nil, unexpectedAt(b, i)
+	}
+
+	return ret, nil
+}
+
+// bounds returns the JSON Data Type of b and the positions where its value
+// starts and ends, ignoring the surrounding whitespace.
+func (d *Discriminator) bounds(b []byte) (t JSONType, start, end int,
+	err error) {
+	if t, err = TypeOf(b); err != nil {
+		return t, 0, 0, err
+	}
+	start = skipSpace(b, 0)
+	return t, start, start + len(trimSpace(b)), nil
+}
+
+// decode decodes the JSON Object in b[start:end].
+func (d *Discriminator) decode(b []byte, start, end int) (interface{}, error) {
+	valStart, valEnd := -1, -1
+	_, err := eachMember(b[:end], start, 1, func(ks, ke, vs, ve int) bool {
+		if keyEquals(b[ks:ke], d.key) {
+			valStart, valEnd = vs, ve
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f := d.fallback
+	switch {
+	case valStart < 0:
+		if f == nil {
+			return nil, fmt.Errorf("%w: %q", ErrNoDiscriminator, d.key)
+		}
+
+	case b[valStart] != '"':
+		t, _ := TypeOf(b[valStart:valEnd])
+		return nil, &UnexpectedTypeError{Got: t, Allowed: []JSONType{String},
+			Offset: int64(valStart)}
+
+	default:
+		value, err := Unquote(b[valStart:valEnd])
+		if err != nil {
+			return nil, err
+		}
+		if rf, ok := d.types[value]; ok {
+			f = rf
+		} else if f == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownDiscriminator, value)
+		}
+	}
+
+	v := f()
+	if err := json.Unmarshal(b[start:end], v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,239 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type discriminatedUser struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type discriminatedGroup struct {
+	Type    string   `json:"type"`
+	Members []string `json:"members"`
+}
+
+func newTestDiscriminator() *Discriminator {
+	return NewDiscriminator("type").
+		Register("user", func() interface{} { return new(discriminatedUser) }).
+		Register("group", func() interface{} { return new(discriminatedGroup) })
+}
+
+func TestDiscriminator_Decode(t *testing.T) {
+	d := newTestDiscriminator()
+	fallback := NewDiscriminator("type").Register("user", func() interface{} {
+		return new(discriminatedUser)
+	}).WithFallback(WithDefaultObject)
+
+	tests := []struct {
+		Name          string
+		Discriminator *Discriminator
+		JSONData      string
+		Want          interface{}
+		Error         error
+	}{
+
+		{
+			Name:          "User",
+			Discriminator: d,
+			JSONData:      ` {"name":"N","type":"user","email":"E"} `,
+			Want: &discriminatedUser{Type: "user", Name: "N",
+				Email: "E"},
+		}, //*/
+
+		{
+			Name:          "Group",
+			Discriminator: d,
+			JSONData:      `{"type":"group","members":["a","b"]}`,
+			Want: &discriminatedGroup{Type: "group",
+				Members: []string{"a", "b"}},
+		}, //*/
+
+		{
+			Name:          "Escaped key and value",
+			Discriminator: d,
+			JSONData:      `{"\u0074ype":"gr\u006fup"}`,
+			Want:          &discriminatedGroup{Type: "group"},
+		}, //*/
+
+		{
+			Name:          "Null",
+			Discriminator: d,
+			JSONData:      `null`,
+		}, //*/
+
+		{
+			Name:          "Missing discriminator",
+			Discriminator: d,
+			JSONData:      `{"name":"N"}`,
+			Error:         ErrNoDiscriminator,
+		}, //*/
+
+		{
+			Name:          "Unknown discriminator",
+			Discriminator: d,
+			JSONData:      `{"type":"robot"}`,
+			Error:         ErrUnknownDiscriminator,
+		}, //*/
+
+		{
+			Name:          "Non-string discriminator",
+			Discriminator: d,
+			JSONData:      `{"type":1}`,
+			Error:         ErrUnexpectedType,
+		}, //*/
+
+		{
+			Name:          "Not an Object",
+			Discriminator: d,
+			JSONData:      `[]`,
+			Error:         ErrUnexpectedType,
+		}, //*/
+
+		{
+			Name:          "Invalid JSON",
+			Discriminator: d,
+			JSONData:      `{"name" "type":"user"}`,
+			Error:         ErrInvalidJSON,
+		}, //*/
+
+		{
+			Name:          "Fallback on missing discriminator",
+			Discriminator: fallback,
+			JSONData:      `{"name":"N"}`,
+			Want:          &map[string]interface{}{"name": "N"},
+		}, //*/
+
+		{
+			Name:          "Fallback on unknown discriminator",
+			Discriminator: fallback,
+			JSONData:      `{"type":"robot"}`,
+			Want:          &map[string]interface{}{"type": "robot"},
+		}, //*/
+
+		{
+			Name:          "Registered with fallback",
+			Discriminator: fallback,
+			JSONData:      `{"type":"user"}`,
+			Want:          &discriminatedUser{Type: "user"},
+		}, //*/
+	}
+
+	for _, test := range tests {
+		v, err := test.Discriminator.Decode([]byte(test.JSONData))
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Name, test.Error,
+				err)
+		}
+		if !reflect.DeepEqual(v, test.Want) {
+			t.Fatalf("[%s] Want: %#v; Have: %#v", test.Name, test.Want, v)
+		}
+	}
+}
+
+func TestDiscriminator_DecodeArray(t *testing.T) {
+	d := newTestDiscriminator()
+
+	in := `[{"type":"user","name":"N"}, null, {"type":"group"}]`
+	v, err := d.DecodeArray([]byte(in))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []interface{}{&discriminatedUser{Type: "user", Name: "N"}, nil,
+		&discriminatedGroup{Type: "group"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("Want: %#v; Have: %#v", want, v)
+	}
+
+	if v, err = d.DecodeArray([]byte(`[]`)); err != nil || v == nil ||
+		len(v) != 0 {
+		t.Fatalf("Unexpected result: %#v (error: %v)", v, err)
+	}
+
+	errTests := []struct {
+		JSONData string
+		Error    error
+	}{
+		{`{"type":"user"}`, ErrUnexpectedType},
+		{`[{"type":"user"},1]`, ErrUnexpectedType},
+		{`[{"type":"robot"}]`, ErrUnknownDiscriminator},
+		{`[{"type":"user"}`, ErrUnexpectedEnd},
+		{`[{"type":"user"}] x`, ErrInvalidJSON},
+	}
+	for _, test := range errTests {
+		v, err := d.DecodeArray([]byte(test.JSONData))
+		if !errors.Is(err, test.Error) || v != nil {
+			t.Fatalf("[%s] Want error: %v; Have: %v (value: %#v)",
+				test.JSONData, test.Error, err, v)
+		}
+	}
+
+	var typeErr *UnexpectedTypeError
+	_, err = d.DecodeArray([]byte(`[{"type":"user"},1]`))
+	if !errors.As(err, &typeErr) || typeErr.Got != Number ||
+		typeErr.Offset != 17 {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+}
+
+func TestPayload_WithDiscriminator(t *testing.T) {
+	var resp struct {
+		Data *Payload `json:"data"`
+	}
+	resp.Data = AcquirePayload().WithDiscriminator(newTestDiscriminator())
+	defer ReleasePayload(resp.Data)
+
+	in := `{"data":{"type":"user","name":"N","email":"E"}}`
+	if err := json.Unmarshal([]byte(in), &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	user, ok := resp.Data.GetObject().(*discriminatedUser)
+	if !ok || user.Name != "N" {
+		t.Fatalf("Unexpected value: %#v", resp.Data.GetObject())
+	}
+	if b, _ := json.Marshal(resp); string(b) != in {
+		t.Fatalf("Want: %s; Have: %s", in, b)
+	}
+
+	in = `{"data":[{"type":"group","members":["N"]}]}`
+	if err := json.Unmarshal([]byte(in), &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	arr, ok := resp.Data.GetArray().(*[]interface{})
+	if !ok || len(*arr) != 1 {
+		t.Fatalf("Unexpected value: %#v", resp.Data.GetArray())
+	}
+	if _, ok = (*arr)[0].(*discriminatedGroup); !ok {
+		t.Fatalf("Unexpected value: %#v", (*arr)[0])
+	}
+	if b, _ := json.Marshal(resp); string(b) != in {
+		t.Fatalf("Want: %s; Have: %s", in, b)
+	}
+
+	err := resp.Data.UnmarshalJSON([]byte(`{"type":"robot"}`))
+	if !errors.Is(err, ErrUnknownDiscriminator) ||
+		resp.Data.GetMapping() != GoInvalidMapping {
+		t.Fatalf("Unexpected result: %v (error: %v)",
+			resp.Data.GetMapping(), err)
+	}
+
+	// Factories set afterwards take precedence for that JSON Data Type.
+	resp.Data.WithArray()
+	if err = resp.Data.UnmarshalJSON([]byte(`[{"type":"robot"}]`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok = resp.Data.GetArray().(*[]interface{}); !ok {
+		t.Fatalf("Unexpected value: %#v", resp.Data.GetArray())
+	}
+
+	resp.Data.WithDiscriminator(nil)
+	err = resp.Data.UnmarshalJSON([]byte(`{"type":"user"}`))
+	if !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+}
@@ -1,9 +1,17 @@
 // Package jsonutils provides various utilities to handle JSON encoded data.
+//
+// The structured errors returned by this package, like *SyntaxError or
+// *DecodeError, wrap an underlying error whenever there is one, so they can be
+// inspected with errors.Is and errors.As.
 package jsonutils
 
 import (
+	"encoding"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"strconv"
 	"unsafe"
 )
 
@@ -18,8 +26,112 @@ const (
 	ErrUnknownType       Error = "unknown type"
 	ErrUnexpectedType    Error = "unexpected JSON type"
 	ErrUnexpectedMapping Error = "unexpected mapping"
+	ErrInvalidString     Error = "invalid JSON string"
+	ErrInvalidJSON       Error = "invalid JSON"
+	ErrUnexpectedEnd     Error = "unexpected end of JSON input"
+	ErrTooDeep           Error = "exceeded max nesting depth"
+	ErrNoRawValue        Error = "no raw value retained"
+	ErrTruncated         Error = "truncated JSON text"
+	ErrDuplicateKey      Error = "duplicate object key"
+
+	ErrNoDiscriminator      Error = "missing discriminator"
+	ErrUnknownDiscriminator Error = "unknown discriminator"
+
+	ErrInvalidPointer Error = "invalid JSON pointer"
+	ErrInvalidIndex   Error = "invalid array index"
+	ErrNotFound       Error = "value not found"
+	ErrInvalidPath    Error = "invalid path"
+
+	ErrInvalidPatch Error = "invalid JSON patch"
+	ErrTestFailed   Error = "test operation failed"
 )
 
+// SyntaxError describes an error found at a specific position of the input,
+// like an unexpected character. Use errors.Is to check for the underlying
+// error, like ErrUnknownType.
+type SyntaxError struct {
+	// Offset is the position in the input where the error was found, counting
+	// from zero.
+	Offset int64
+	// Char is the offending byte, or zero if the end of the input was reached.
+	Char byte
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Char == 0 {
+		return fmt.Sprintf("%v at offset %d", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("%v: invalid character %q at offset %d", e.Err, e.Char,
+		e.Offset)
+}
+
+// Unwrap returns the underlying error.
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
+// newSyntaxError returns a *SyntaxError for the position i of b, which can be
+// past the end of b.
+func newSyntaxError(b []byte, i int, err error) *SyntaxError {
+	e := &SyntaxError{Offset: int64(i), Err: err}
+	if i < len(b) {
+		e.Char = b[i]
+	}
+	return e
+}
+
+// UnexpectedTypeError describes a JSON value whose JSON Data Type was not
+// allowed. It matches ErrUnexpectedType when using errors.Is.
+type UnexpectedTypeError struct {
+	// Got is the JSON Data Type found.
+	Got JSONType
+	// Allowed are the JSON Data Types that were expected.
+	Allowed []JSONType
+	// Offset is the position in the input where the value starts.
+	Offset int64
+}
+
+func (e *UnexpectedTypeError) Error() string {
+	return fmt.Sprintf("expected one of %v, got %v", e.Allowed, e.Got)
+}
+
+// Is reports whether target is ErrUnexpectedType.
+func (e *UnexpectedTypeError) Is(target error) bool {
+	return target == ErrUnexpectedType
+}
+
+// NumberError describes a JSON Number that could not be decoded into the Go
+// type selected by its GoMapping, like a fraction when an integer was expected
+// or a value that overflows.
+type NumberError struct {
+	// Num is the text of the JSON Number.
+	Num string
+	// Mapping is the GoMapping that was attempted.
+	Mapping GoMapping
+	// Offset is the position in the input where the value starts.
+	Offset int64
+	// Err is the underlying error, usually strconv.ErrSyntax or
+	// strconv.ErrRange.
+	Err error
+}
+
+// newNumberError returns a *NumberError, unwrapping err if it's a
+// *strconv.NumError since its details are already held by the NumberError.
+func newNumberError(num string, m GoMapping, offset int, err error) error {
+	if e := (*strconv.NumError)(nil); errors.As(err, &e) {
+		err = e.Err
+	}
+	return &NumberError{Num: num, Mapping: m, Offset: int64(offset), Err: err}
+}
+
+func (e *NumberError) Error() string {
+	return fmt.Sprintf("cannot decode number %s as %v: %v", e.Num, e.Mapping,
+		e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *NumberError) Unwrap() error { return e.Err }
+
 // JSONType identifies one of the stardad JSON Data Types.
 type JSONType uint8
 
@@ -40,6 +152,53 @@ const (
 	maxJSONType
 )
 
+var jsonTypeNames = [maxJSONType]string{
+	InvalidJSON: "invalid",
+	Object:      "object",
+	Array:       "array",
+	Null:        "null",
+	String:      "string",
+	Number:      "number",
+	Boolean:     "boolean",
+}
+
+// Assert at compile-time that we implement the text encoding interfaces.
+var (
+	_ fmt.Stringer             = JSONType(0)
+	_ encoding.TextMarshaler   = JSONType(0)
+	_ encoding.TextUnmarshaler = (*JSONType)(nil)
+)
+
+// String returns the canonical lowercase name of the JSON Data Type, like
+// "object" or "number".
+func (t JSONType) String() string {
+	if t < maxJSONType {
+		return jsonTypeNames[t]
+	}
+	return "JSONType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, using the same
+// names as String.
+func (t JSONType) MarshalText() ([]byte, error) {
+	if t >= maxJSONType {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownType, t)
+	}
+	return []byte(jsonTypeNames[t]), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting
+// the names returned by String.
+func (t *JSONType) UnmarshalText(text []byte) error {
+	for i, name := range jsonTypeNames {
+		if name == string(text) {
+			*t = JSONType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownType, text)
+}
+
 // GoMapping specifies how was the JSON Data Type mapped into a Go Type. This
 // information is used to understand how can be retrieved the value from the
 // Payload once processed.
@@ -63,19 +222,102 @@ const (
 	// retrieved as a string with GetString.
 	GoString
 	// GoInt means that the JSON value was a Number and that WithNumber or
-	// WithInt were used. The value can be retrieved as an int64 with GetInt.
+	// WithInt were used, or that WithAutoNumber was used and the Number was an
+	// integer that fits in an int64. The value can be retrieved as an int64
+	// with GetInt.
 	GoInt
 	// GoFloat means that the JSON value was a Number and that the WithFloat was
-	// used. The value can be retrieved as a float64 with GetFloat.
+	// used, or that WithAutoNumber was used and the Number was not an integer
+	// that fits in an int64 or a uint64. The value can be retrieved as a
+	// float64 with GetFloat.
 	GoFloat
 	// GoUint means that the JSON value was a Number and that the WithUint was
-	// used. The value can be retrieved as a float64 with GetUint.
+	// used, or that WithAutoNumber was used and the Number was an integer that
+	// only fits in a uint64. The value can be retrieved as a float64 with
+	// GetUint.
 	GoUint
 	// GoBool means that the JSON value was a Boolean and that the WithBool was
 	// used. The value can be retrieved as a bool with GetBool.
 	GoBool
+	// GoJSONNumber means that the JSON value was a Number and that the
+	// WithJSONNumber was used. The value can be retrieved as a json.Number with
+	// GetJSONNumber.
+	GoJSONNumber
+	// GoBigInt means that the JSON value was a Number and that the WithBigInt
+	// was used. The value can be retrieved as a *big.Int with GetBigInt.
+	GoBigInt
+	// GoBigFloat means that the JSON value was a Number and that the
+	// WithBigFloat was used. The value can be retrieved as a *big.Float with
+	// GetBigFloat.
+	GoBigFloat
+	// GoBigRat means that the JSON value was a Number and that the WithBigRat
+	// was used. The value can be retrieved as a *big.Rat with GetBigRat.
+	GoBigRat
+	// GoRaw means that the JSON value was an Array or an Object and that
+	// WithLazyDecoding was used, so it was not decoded. The value can be
+	// retrieved as a json.RawMessage with GetRaw, or decoded with DecodeInto.
+	GoRaw
+
+	// Internal use. Keep at the end of this block.
+
+	// goAutoNumber is only used as a hint for the unmarshaler to choose the
+	// mapping of Number values.
+	goAutoNumber
+)
+
+var goMappingNames = [...]string{
+	GoInvalidMapping: "invalid",
+	GoOther:          "other",
+	GoNil:            "nil",
+	GoString:         "string",
+	GoInt:            "int",
+	GoFloat:          "float",
+	GoUint:           "uint",
+	GoBool:           "bool",
+	GoJSONNumber:     "jsonnumber",
+	GoBigInt:         "bigint",
+	GoBigFloat:       "bigfloat",
+	GoBigRat:         "bigrat",
+	GoRaw:            "raw",
+}
+
+// Assert at compile-time that we implement the text encoding interfaces.
+var (
+	_ fmt.Stringer             = GoMapping(0)
+	_ encoding.TextMarshaler   = GoMapping(0)
+	_ encoding.TextUnmarshaler = (*GoMapping)(nil)
 )
 
+// String returns the canonical lowercase name of the mapping, like "string" or
+// "bigint".
+func (m GoMapping) String() string {
+	if int(m) < len(goMappingNames) {
+		return goMappingNames[m]
+	}
+	return "GoMapping(" + strconv.Itoa(int(m)) + ")"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, using the same
+// names as String.
+func (m GoMapping) MarshalText() ([]byte, error) {
+	if int(m) >= len(goMappingNames) {
+		return nil, fmt.Errorf("%w: %v", ErrUnexpectedMapping, m)
+	}
+	return []byte(goMappingNames[m]), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting
+// the names returned by String.
+func (m *GoMapping) UnmarshalText(text []byte) error {
+	for i, name := range goMappingNames {
+		if name == string(text) {
+			*m = GoMapping(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnexpectedMapping, text)
+}
+
 func (m GoMapping) panicIfNot(m2 GoMapping) {
 	if m != m2 {
 		panic(ErrUnexpectedMapping)
@@ -114,6 +356,29 @@ var (
 	bFalse = []byte{'f', 'a', 'l', 's', 'e'}
 )
 
+// isSpace reports whether c is insignificant whitespace, as defined by RFC 8259.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// skipSpace returns the position of the first non-whitespace byte of b starting
+// at i, or len(b) if there is none.
+func skipSpace(b []byte, i int) int {
+	for i < len(b) && isSpace(b[i]) {
+		i++
+	}
+	return i
+}
+
+// trimSpace returns b without leading and trailing whitespace.
+func trimSpace(b []byte) []byte {
+	b = b[skipSpace(b, 0):]
+	for len(b) > 0 && isSpace(b[len(b)-1]) {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
 // bytesToString converts from []byte to string with no memory allocation.
 // Could eventually break if headers for slice or string types are modified.
 // See:
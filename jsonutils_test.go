@@ -1,6 +1,11 @@
 package jsonutils
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
 
 func TestUtils(t *testing.T) {
 	var m GoMapping
@@ -31,3 +36,64 @@ func TestUtils(t *testing.T) {
 
 	dummy.Unlock()
 }
+
+func TestJSONType_Text(t *testing.T) {
+	for jType := InvalidJSON; jType < maxJSONType; jType++ {
+		b, err := jType.MarshalText()
+		if err != nil || string(b) != jType.String() {
+			t.Fatalf("Want: %s; Have: %s (error: %v)", jType, b, err)
+		}
+		var have JSONType
+		if err = have.UnmarshalText(b); err != nil || have != jType {
+			t.Fatalf("Want: %v; Have: %v (error: %v)", jType, have, err)
+		}
+	}
+
+	// Useful for configuration files.
+	var allowed []JSONType
+	err := json.Unmarshal([]byte(`["string","object","null"]`), &allowed)
+	if err != nil || fmt.Sprint(allowed) != "[string object null]" {
+		t.Fatalf("Unexpected result: %v (error: %v)", allowed, err)
+	}
+	if b, _ := json.Marshal(allowed); string(b) != `["string","object","null"]` {
+		t.Fatalf("Unexpected result: %s", b)
+	}
+
+	if s := maxJSONType.String(); s != "JSONType(7)" {
+		t.Fatalf("Want: %s; Have: %s", "JSONType(7)", s)
+	}
+	if _, err = maxJSONType.MarshalText(); !errors.Is(err, ErrUnknownType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnknownType, err)
+	}
+	var jType JSONType
+	if err = jType.UnmarshalText([]byte("Object")); !errors.Is(err,
+		ErrUnknownType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnknownType, err)
+	}
+}
+
+func TestGoMapping_Text(t *testing.T) {
+	for m := GoInvalidMapping; m <= GoRaw; m++ {
+		b, err := m.MarshalText()
+		if err != nil || string(b) != m.String() || len(b) == 0 {
+			t.Fatalf("Want: %s; Have: %s (error: %v)", m, b, err)
+		}
+		var have GoMapping
+		if err = have.UnmarshalText(b); err != nil || have != m {
+			t.Fatalf("Want: %v; Have: %v (error: %v)", m, have, err)
+		}
+	}
+
+	if s := goAutoNumber.String(); s != "GoMapping(13)" {
+		t.Fatalf("Want: %s; Have: %s", "GoMapping(13)", s)
+	}
+	if _, err := goAutoNumber.MarshalText(); !errors.Is(err,
+		ErrUnexpectedMapping) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedMapping, err)
+	}
+	var m GoMapping
+	if err := m.UnmarshalText([]byte("auto")); !errors.Is(err,
+		ErrUnexpectedMapping) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedMapping, err)
+	}
+}
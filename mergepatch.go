@@ -0,0 +1,202 @@
+package jsonutils
+
+import "bytes"
+
+// member is a member of a JSON Object, holding the raw bytes of its name and
+// value, as well as its name already unquoted.
+type member struct {
+	name    string
+	rawName []byte
+	value   []byte
+}
+
+// MergePatch applies the JSON Merge Patch (RFC 7386) patch to the JSON
+// document target, and returns the patched document.
+//
+// If patch is not a JSON Object, then it replaces target altogether, as the
+// RFC requires. Otherwise, each member of patch is merged into target
+// recursively, and the members set to JSON Null are removed.
+//
+// The merge is done over the raw bytes, without decoding the values: the
+// members of target keep their order, and the values not modified by patch
+// are copied unchanged, while new members are appended in the order of patch.
+// Both target and patch must be valid JSON, and a *SyntaxError is returned
+// otherwise.
+func MergePatch(target, patch []byte) ([]byte, error) {
+	if _, err := TypeOfStrict(target); err != nil {
+		return nil, err
+	}
+	if _, err := TypeOfStrict(patch); err != nil {
+		return nil, err
+	}
+	return mergeValues(nil, trimSpace(target), trimSpace(patch)), nil
+}
+
+// mergeValues appends to buf the result of merging the valid JSON value patch
+// into the valid JSON value target, which can be nil if there is no target.
+func mergeValues(buf, target, patch []byte) []byte {
+	if t, _ := TypeOf(patch); t != Object {
+		return append(buf, patch...)
+	}
+
+	var targetMembers []member
+	if t, _ := TypeOf(target); t == Object {
+		targetMembers = objectMembers(target)
+	}
+	patchMembers := objectMembers(patch)
+
+	// The last duplicate member of patch wins, like in encoding/json.
+	patchIndex := make(map[string]int, len(patchMembers))
+	for i, m := range patchMembers {
+		patchIndex[m.name] = i
+	}
+
+	buf = append(buf, '{')
+	n := 0
+	write := func(rawName []byte) {
+		if n > 0 {
+			buf = append(buf, ',')
+		}
+		n++
+		buf = append(buf, rawName...)
+		buf = append(buf, ':')
+	}
+
+	inTarget := make(map[string]bool, len(targetMembers))
+	for _, m := range targetMembers {
+		inTarget[m.name] = true
+		i, ok := patchIndex[m.name]
+		switch {
+		case !ok:
+			write(m.rawName)
+			buf = append(buf, m.value...)
+		case !isNullValue(patchMembers[i].value):
+			write(m.rawName)
+			buf = mergeValues(buf, m.value, patchMembers[i].value)
+		}
+	}
+
+	for i, m := range patchMembers {
+		if patchIndex[m.name] != i || inTarget[m.name] ||
+			isNullValue(m.value) {
+			continue
+		}
+		write(m.rawName)
+		buf = mergeValues(buf, nil, m.value)
+	}
+
+	return append(buf, '}')
+}
+
+// CreateMergePatch returns a JSON Merge Patch (RFC 7386) document that
+// transforms the JSON document original into updated when applied with
+// MergePatch.
+//
+// If either of them is not a JSON Object, then the patch is updated itself.
+// Otherwise, the patch has the members that were modified or added, and the
+// removed members set to JSON Null, in the order of original followed by the
+// new members in the order of updated. The values are copied unchanged from
+// updated, and JSON Numbers are compared by their numeric value.
+//
+// Note that JSON Merge Patch cannot set a value to JSON Null, since it means
+// removing the member, so those members end up removed when applying the
+// patch. Both original and updated must be valid JSON, and a *SyntaxError is
+// returned otherwise.
+func CreateMergePatch(original, updated []byte) ([]byte, error) {
+	if _, err := TypeOfStrict(original); err != nil {
+		return nil, err
+	}
+	if _, err := TypeOfStrict(updated); err != nil {
+		return nil, err
+	}
+	original, updated = trimSpace(original), trimSpace(updated)
+
+	patch, _ := diffMerge(nil, original, updated)
+	return patch, nil
+}
+
+// diffMerge appends to buf the JSON Merge Patch that transforms the valid JSON
+// value original into updated, and reports whether there are any changes.
+func diffMerge(buf, original, updated []byte) ([]byte, bool) {
+	to, _ := TypeOf(original)
+	tu, _ := TypeOf(updated)
+	if to != Object || tu != Object {
+		return append(buf, updated...), !equalValues(original, updated)
+	}
+
+	updatedMembers := objectMembers(updated)
+	updatedIndex := make(map[string]int, len(updatedMembers))
+	for i, m := range updatedMembers {
+		updatedIndex[m.name] = i
+	}
+
+	buf = append(buf, '{')
+	start, n := len(buf), 0
+	write := func(rawName []byte) {
+		if n > 0 {
+			buf = append(buf, ',')
+		}
+		n++
+		buf = append(buf, rawName...)
+		buf = append(buf, ':')
+	}
+
+	inOriginal := map[string]bool{}
+	for _, m := range objectMembers(original) {
+		if inOriginal[m.name] {
+			continue
+		}
+		inOriginal[m.name] = true
+
+		i, ok := updatedIndex[m.name]
+		if !ok {
+			write(m.rawName)
+			buf = append(buf, bNull...)
+			continue
+		}
+
+		// Write the member and discard it if there were no changes.
+		mark, markN := len(buf), n
+		write(m.rawName)
+		var changed bool
+		if buf, changed = diffMerge(buf, m.value,
+			updatedMembers[i].value); !changed {
+			buf, n = buf[:mark], markN
+		}
+	}
+
+	for i, m := range updatedMembers {
+		if updatedIndex[m.name] == i && !inOriginal[m.name] {
+			write(m.rawName)
+			buf = append(buf, m.value...)
+		}
+	}
+
+	return append(buf, '}'), len(buf) > start
+}
+
+// objectMembers returns the members of the valid JSON Object b, in order.
+func objectMembers(b []byte) []member {
+	var ret []member
+	eachMember(b, 0, 1, func(ks, ke, vs, ve int) bool {
+		name, _ := Unquote(b[ks:ke])
+		ret = append(ret, member{name: name, rawName: b[ks:ke],
+			value: b[vs:ve]})
+		return true
+	})
+	return ret
+}
+
+// isNullValue reports whether the valid JSON value b is JSON Null.
+func isNullValue(b []byte) bool { return bytes.Equal(b, bNull) }
+
+// equalValues reports whether the valid JSON values a and b are equal. JSON
+// Numbers are compared by their numeric value.
+func equalValues(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	ta, _ := decodeTree(a)
+	tb, _ := decodeTree(b)
+	return equalTrees(ta, tb)
+}
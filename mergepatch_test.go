@@ -0,0 +1,94 @@
+package jsonutils
+
+import (
+	"errors"
+	"testing"
+)
+
+// MergePatchTestsRaw are the examples from RFC 7386 appendix A, and some more.
+var MergePatchTestsRaw = []struct {
+	Target string
+	Patch  string
+	Want   string
+}{
+	{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+	{`{"a":"b"}`, `{"a":null}`, `{}`},
+	{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+	{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+	{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+	{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+	{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+	{`{"a":"b"}`, `["c"]`, `["c"]`},
+	{`{"a":"foo"}`, `null`, `null`},
+	{`{"a":"foo"}`, `"bar"`, `"bar"`},
+	{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+	{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+	{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+
+	// The order of the target members and the untouched values are preserved.
+	{` {"z": 1, "a": [1, 2], "m": 1.50} `, `{"a":null,"b":2,"z":3}`,
+		`{"z":3,"m":1.50,"b":2}`},
+	{`{"z":{"y": 1e2,"x":2},"a":0}`, `{"z":{"x":null,"w":"<"}}`,
+		`{"z":{"y":1e2,"w":"<"},"a":0}`},
+}
+
+func TestMergePatch(t *testing.T) {
+	for _, test := range MergePatchTestsRaw {
+		have, err := MergePatch([]byte(test.Target), []byte(test.Patch))
+		if err != nil {
+			t.Fatalf("[%s %s] Unexpected error: %v", test.Target, test.Patch,
+				err)
+		}
+		if string(have) != test.Want {
+			t.Fatalf("[%s %s] Want: %s; Have: %s", test.Target, test.Patch,
+				test.Want, have)
+		}
+	}
+
+	for _, test := range [][2]string{{`{`, `{}`}, {`{}`, `{"a"}`}, {``, `1`}} {
+		_, err := MergePatch([]byte(test[0]), []byte(test[1]))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) && !errors.Is(err, ErrEmpty) {
+			t.Fatalf("[%s %s] Unexpected error: %v", test[0], test[1], err)
+		}
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := []struct {
+		Original, Updated string
+		Want              string
+	}{
+		{`{"a":1}`, `{"a":1.0}`, `{}`},
+		{`{"a":"b","c":{"d":"e","f":"g"}}`, `{"a":"z","c":{"d":"e"}}`,
+			`{"a":"z","c":{"f":null}}`},
+		{`{"b":1,"a":2}`, `{"c":[3],"a":2}`, `{"b":null,"c":[3]}`},
+		{`{"a":{"b":1}}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"a":1}`, `[1]`, `[1]`},
+		{`1`, ` 2 `, `2`},
+	}
+
+	for _, test := range tests {
+		patch, err := CreateMergePatch([]byte(test.Original),
+			[]byte(test.Updated))
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.Original, err)
+		}
+		if string(patch) != test.Want {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.Original, test.Want,
+				patch)
+		}
+
+		// Applying the patch transforms Original into Updated.
+		have, err := MergePatch([]byte(test.Original), patch)
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.Original, err)
+		}
+		if !equalValues(have, trimSpace([]byte(test.Updated))) {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.Original, test.Updated,
+				have)
+		}
+	}
+}
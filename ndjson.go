@@ -0,0 +1,247 @@
+package jsonutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// recordSeparator starts each JSON text in a JSON Text Sequence (RFC 7464).
+const recordSeparator = 0x1E
+
+// LineError is returned by LineReader when a line could not be read or
+// decoded, telling which line of the input was at fault.
+type LineError struct {
+	Line int   // 1-based number of the line, or record
+	Err  error // The underlying error, like a *SyntaxError or ErrTruncated
+}
+
+// Error returns the underlying error message prefixed by the line number.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *LineError) Unwrap() error { return e.Err }
+
+// LineReader reads newline-delimited JSON (also known as NDJSON or JSON
+// Lines), where each line holds a JSON value that may be of any JSON Data Type.
+// Blank lines are skipped.
+//
+// With WithSequence, it reads JSON Text Sequences (RFC 7464) instead, where
+// each JSON value is preceded by an ASCII Record Separator (0x1E).
+//
+// Its interface is like that of bufio.Scanner:
+//
+//	r := NewLineReader(f)
+//	for r.Next() {
+//		fmt.Println(r.Line(), r.Type(), string(r.Bytes()))
+//	}
+//	if err := r.Err(); err != nil {
+//		// handle error
+//	}
+//
+// LineReader is not safe to use in concurrent goroutines.
+type LineReader struct {
+	s        *bufio.Scanner
+	sequence bool
+
+	line    int
+	raw     []byte
+	jType   JSONType
+	typeErr error
+	err     error
+}
+
+// NewLineReader returns a new LineReader that reads from r. The default
+// maximum line length is bufio.MaxScanTokenSize.
+func NewLineReader(r io.Reader) *LineReader {
+	lr := &LineReader{s: bufio.NewScanner(r)}
+	lr.s.Split(lr.split)
+	return lr
+}
+
+// WithMaxLineLength sets the maximum length of a line, or record, including
+// its delimiters. Longer lines stop the reading with an error matching
+// bufio.ErrTooLong. It must be called before reading.
+func (r *LineReader) WithMaxLineLength(n int) *LineReader {
+	size := n
+	if size > 4096 {
+		size = 4096
+	}
+	r.s.Buffer(make([]byte, 0, size), n)
+	return r
+}
+
+// WithSequence configures the LineReader to read JSON Text Sequences
+// (RFC 7464) instead of newline-delimited JSON (disabled by default). It must
+// be called before reading.
+//
+// As required by the RFC, a JSON Number, Boolean or Null that is not followed
+// by whitespace is considered truncated, and its Type is InvalidJSON.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (r *LineReader) WithSequence(enable ...bool) *LineReader {
+	r.sequence = len(enable) == 0 || enable[0]
+	return r
+}
+
+// Next advances to the next non-blank line, which is then available through
+// the Bytes, Type and Line methods. It returns false when there are no more
+// lines or an error occurred, which is then returned by Err.
+func (r *LineReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	for r.s.Scan() {
+		r.line++
+		r.raw = trimSpace(r.s.Bytes())
+		if len(r.raw) == 0 {
+			continue
+		}
+
+		r.jType, r.typeErr = TypeOf(r.raw)
+		if r.typeErr == nil && r.sequence && r.isTruncated() {
+			r.jType, r.typeErr = InvalidJSON, ErrTruncated
+		}
+		return true
+	}
+
+	r.raw, r.jType, r.typeErr = nil, InvalidJSON, nil
+	if err := r.s.Err(); err != nil {
+		r.err = &LineError{Line: r.line + 1, Err: err}
+	}
+	return false
+}
+
+// isTruncated reports whether the current record is a JSON Number, Boolean or
+// Null without trailing whitespace.
+func (r *LineReader) isTruncated() bool {
+	b := r.s.Bytes()
+	switch r.jType {
+	case Number, Boolean, Null:
+		return !isSpace(b[len(b)-1])
+	}
+	return false
+}
+
+// Bytes returns the current line without surrounding whitespace. The
+// underlying array may be overwritten by the next call to Next.
+func (r *LineReader) Bytes() []byte { return r.raw }
+
+// Type returns the JSON Data Type of the current line, as reported by TypeOf.
+// It's InvalidJSON if the line is not valid, and then Decode returns the
+// reason.
+func (r *LineReader) Type() JSONType { return r.jType }
+
+// Line returns the 1-based number of the current line, counting blank lines.
+// When reading JSON Text Sequences, it's the number of the current record.
+func (r *LineReader) Line() int { return r.line }
+
+// Err returns the first error that stopped the reading, if any, as a
+// *LineError.
+func (r *LineReader) Err() error { return r.err }
+
+// Decode decodes the current line into p, which should be configured to
+// accept it. It returns a *LineError if it fails.
+func (r *LineReader) Decode(p *Payload) error {
+	err := r.typeErr
+	if err == nil {
+		err = p.UnmarshalJSON(r.raw)
+	}
+	if err != nil {
+		return &LineError{Line: r.line, Err: err}
+	}
+	return nil
+}
+
+// split is the bufio.SplitFunc of the LineReader.
+func (r *LineReader) split(data []byte, atEOF bool) (int, []byte, error) {
+	if !r.sequence {
+		return bufio.ScanLines(data, atEOF)
+	}
+
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	start := 0
+	if data[0] == recordSeparator {
+		start = 1
+	}
+	if i := bytes.IndexByte(data[start:], recordSeparator); i >= 0 {
+		return start + i, data[start : start+i], nil
+	}
+	if atEOF {
+		return len(data), data[start:], nil
+	}
+	return 0, nil, nil
+}
+
+// LineWriter writes newline-delimited JSON (also known as NDJSON or JSON
+// Lines), guaranteeing that each value is written compacted in a single line.
+//
+// With WithSequence, it writes JSON Text Sequences (RFC 7464) instead, where
+// each JSON value is also preceded by an ASCII Record Separator (0x1E).
+//
+// Each value is written with a single call to the underlying io.Writer.
+// LineWriter is not safe to use in concurrent goroutines.
+type LineWriter struct {
+	w        io.Writer
+	buf      bytes.Buffer
+	enc      *json.Encoder
+	sequence bool
+}
+
+// NewLineWriter returns a new LineWriter that writes to w.
+func NewLineWriter(w io.Writer) *LineWriter {
+	lw := &LineWriter{w: w}
+	lw.enc = json.NewEncoder(&lw.buf)
+	return lw
+}
+
+// WithSequence configures the LineWriter to write JSON Text Sequences
+// (RFC 7464) instead of newline-delimited JSON (disabled by default).
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (w *LineWriter) WithSequence(enable ...bool) *LineWriter {
+	w.sequence = len(enable) == 0 || enable[0]
+	return w
+}
+
+// Encode writes the JSON encoding of v, as returned by encoding/json, in a
+// single line.
+func (w *LineWriter) Encode(v interface{}) error {
+	w.start()
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+// EncodeRaw writes the JSON value b, compacted, in a single line. It returns
+// an error if b is not a valid JSON value.
+func (w *LineWriter) EncodeRaw(b []byte) error {
+	w.start()
+	if err := json.Compact(&w.buf, b); err != nil {
+		return err
+	}
+	w.buf.WriteByte('\n')
+	return w.flush()
+}
+
+func (w *LineWriter) start() {
+	w.buf.Reset()
+	if w.sequence {
+		w.buf.WriteByte(recordSeparator)
+	}
+}
+
+func (w *LineWriter) flush() error {
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
@@ -0,0 +1,179 @@
+package jsonutils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type lineTest struct {
+	Line  int
+	Type  JSONType
+	Bytes string
+}
+
+func readLines(t *testing.T, r *LineReader) []lineTest {
+	t.Helper()
+	var ret []lineTest
+	for r.Next() {
+		ret = append(ret, lineTest{r.Line(), r.Type(), string(r.Bytes())})
+	}
+	return ret
+}
+
+func checkLines(t *testing.T, want, have []lineTest) {
+	t.Helper()
+	if len(have) != len(want) {
+		t.Fatalf("Want: %v; Have: %v", want, have)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Fatalf("Want: %v; Have: %v", want, have)
+		}
+	}
+}
+
+func TestLineReader(t *testing.T) {
+	in := "{\"a\":1}\n\n  [1, 2]  \r\n\"str\"\n \t \n42\nnull\n{garbage"
+	r := NewLineReader(strings.NewReader(in))
+
+	checkLines(t, []lineTest{
+		{1, Object, `{"a":1}`},
+		{3, Array, `[1, 2]`},
+		{4, String, `"str"`},
+		{6, Number, `42`},
+		{7, Null, `null`},
+		{8, Object, `{garbage`},
+	}, readLines(t, r))
+	if err := r.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestLineReader_Decode(t *testing.T) {
+	in := "1\n\"two\"\n3.5\n"
+	r := NewLineReader(strings.NewReader(in))
+	p := AcquirePayload().WithInt()
+	defer ReleasePayload(p)
+
+	var sum int64
+	var lineErr *LineError
+	for r.Next() {
+		err := r.Decode(p)
+		switch r.Line() {
+		case 1:
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			sum += p.GetInt()
+		case 2:
+			if !errors.As(err, &lineErr) || lineErr.Line != 2 ||
+				!errors.Is(err, ErrUnexpectedType) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		case 3:
+			if !errors.As(err, &lineErr) || lineErr.Line != 3 {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+	}
+	if sum != 1 {
+		t.Fatalf("Want: %d; Have: %d", 1, sum)
+	}
+}
+
+func TestLineReader_MaxLineLength(t *testing.T) {
+	in := "1\n\"" + strings.Repeat("x", 100) + "\"\n3\n"
+	r := NewLineReader(strings.NewReader(in)).WithMaxLineLength(64)
+
+	checkLines(t, []lineTest{{1, Number, `1`}}, readLines(t, r))
+
+	var lineErr *LineError
+	err := r.Err()
+	if !errors.As(err, &lineErr) || lineErr.Line != 2 ||
+		!errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.Next() {
+		t.Fatalf("Unexpected line after an error")
+	}
+}
+
+func TestLineReader_Sequence(t *testing.T) {
+	in := "\x1e{\"a\":\n1}\n\x1e\n\x1e42\n\x1e\"str\"\n\x1etrue\x1e12"
+	r := NewLineReader(strings.NewReader(in)).WithSequence()
+
+	checkLines(t, []lineTest{
+		{1, Object, "{\"a\":\n1}"},
+		{3, Number, `42`},
+		{4, String, `"str"`},
+		{5, InvalidJSON, `true`},
+		{6, InvalidJSON, `12`},
+	}, readLines(t, r))
+
+	r = NewLineReader(strings.NewReader("\x1e12")).WithSequence()
+	p := AcquirePayload().WithInt()
+	defer ReleasePayload(p)
+	if !r.Next() {
+		t.Fatalf("Want a record")
+	}
+	if err := r.Decode(p); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("Want: %v; Have: %v", ErrTruncated, err)
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLineWriter(&buf)
+
+	p := AcquirePayload().WithString()
+	defer ReleasePayload(p)
+	if err := p.SetString("a\nb"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, v := range []interface{}{map[string]int{"a": 1}, p, "<b>"} {
+		if err := w.Encode(v); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := w.EncodeRaw([]byte("{\n  \"a\": [1, 2]\n}")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := w.EncodeRaw([]byte("{")); err == nil {
+		t.Fatalf("Want an error")
+	}
+
+	want := "{\"a\":1}\n\"a\\nb\"\n\"\\u003cb\\u003e\"\n{\"a\":[1,2]}\n"
+	if buf.String() != want {
+		t.Fatalf("Want: %q; Have: %q", want, buf.String())
+	}
+
+	// Round-trip through a LineReader.
+	r := NewLineReader(&buf)
+	if n := len(readLines(t, r)); n != 4 {
+		t.Fatalf("Want: %d; Have: %d", 4, n)
+	}
+}
+
+func TestLineWriter_Sequence(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLineWriter(&buf).WithSequence()
+	if err := w.Encode(42); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := w.EncodeRaw([]byte(` [ true ] `)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "\x1e42\n\x1e[true]\n"
+	if buf.String() != want {
+		t.Fatalf("Want: %q; Have: %q", want, buf.String())
+	}
+
+	r := NewLineReader(&buf).WithSequence()
+	checkLines(t, []lineTest{{1, Number, `42`}, {2, Array, `[true]`}},
+		readLines(t, r))
+}
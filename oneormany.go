@@ -0,0 +1,105 @@
+package jsonutils
+
+import "encoding/json"
+
+// Shape describes whether a JSON value was a single value or an Array of them.
+type Shape uint8
+
+const (
+	// ShapeUnknown is not a shape itself but it's used for control purposes in
+	// this package (like signaling that no value was received).
+	ShapeUnknown Shape = iota
+
+	// ShapeOne is a single JSON value, other than an Array.
+	ShapeOne
+	// ShapeMany is a JSON Array.
+	ShapeMany
+)
+
+// OneOrMany handles the common API pattern of returning either a single value
+// or an Array of them, like a single object when only one result matched and
+// an array of objects otherwise.
+//
+// The JSON value is always decoded into Values. A JSON Array is decoded as a
+// []T, while any other JSON value (except for Null) is decoded as a T and
+// stored as the only element of Values. A JSON Null or a decoding error clear
+// Values.
+//
+// OneOrMany remembers the Shape it received, and it is encoded back into that
+// Shape unless MarshalAs says otherwise. Note that T should not be a type that
+// is itself encoded as a JSON Array, because then the single value and the
+// Array of them could not be told apart.
+type OneOrMany[T any] struct {
+	Values []T
+
+	// MarshalAs sets the Shape used when encoding. Its zero value,
+	// ShapeUnknown, means that the Shape received is used. Values can only be
+	// encoded with ShapeOne if they hold exactly one element, otherwise
+	// they're encoded with ShapeMany.
+	MarshalAs Shape
+
+	received Shape
+}
+
+// Assert at compile-time that we implement the JSON Marshaler and Unmarshaler
+// interfaces.
+var (
+	_ json.Marshaler   = OneOrMany[int]{}
+	_ json.Unmarshaler = (*OneOrMany[int])(nil)
+)
+
+// Received returns the Shape of the last JSON value decoded. It's ShapeUnknown
+// if nothing was decoded yet, if the value was a JSON Null or if an error
+// occurred.
+func (o *OneOrMany[T]) Received() Shape { return o.received }
+
+// UnmarshalJSON implements the JSON Unmarshaler interface.
+func (o *OneOrMany[T]) UnmarshalJSON(b []byte) error {
+	o.received = ShapeUnknown
+
+	jType, err := TypeOf(b)
+	if err != nil {
+		return err
+	}
+
+	switch jType {
+	case Null:
+		o.Values = nil
+		return nil
+
+	case Array:
+		if err = json.Unmarshal(b, &o.Values); err != nil {
+			o.Values = nil
+			return err
+		}
+		o.received = ShapeMany
+
+	default:
+		var zero T
+		o.Values = append(o.Values[:0], zero)
+		if err = json.Unmarshal(b, &o.Values[0]); err != nil {
+			o.Values = nil
+			return err
+		}
+		o.received = ShapeOne
+	}
+
+	return nil
+}
+
+// MarshalJSON implements the JSON Marshaler interface.
+func (o OneOrMany[T]) MarshalJSON() ([]byte, error) {
+	shape := o.MarshalAs
+	if shape == ShapeUnknown {
+		shape = o.received
+	}
+
+	switch {
+	case shape == ShapeOne && len(o.Values) == 1:
+		return json.Marshal(o.Values[0])
+	case shape != ShapeUnknown && o.Values == nil:
+		return []byte{'[', ']'}, nil
+	}
+
+	return json.Marshal(o.Values)
+}
@@ -0,0 +1,38 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+func ExampleOneOrMany() {
+	// The GET Users endpoint returns a single user object if only one matched
+	// the criteria, and an array of users otherwise.
+	type User struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	}
+	type Response struct {
+		Status int             `json:"status"`
+		Data   OneOrMany[User] `json:"data"`
+	}
+
+	payloads := []string{
+		`{"status":200,"data":{"id":1,"username":"johndoe"}}`,
+		`{"status":200,"data":[{"id":1,"username":"johndoe"},` +
+			`{"id":2,"username":"jeandoe"}]}`,
+	}
+
+	for _, payload := range payloads {
+		var resp Response
+		if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(len(resp.Data.Values), resp.Data.Values[0].Username)
+	}
+
+	// Output:
+	// 1 johndoe
+	// 2 johndoe
+}
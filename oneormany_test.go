@@ -0,0 +1,193 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type OneOrManyResponse struct {
+	Status int            `json:"status"`
+	Data   OneOrMany[Tag] `json:"data"`
+}
+
+var OneOrManyTestsRaw = []struct {
+	Name          string
+	Payload       []byte
+	MarshalAs     Shape
+	Error         string
+	Values        int
+	Received      Shape
+	MarshaledBack string
+}{
+
+	{
+		Name:          "Object",
+		Payload:       []byte(`{"status":200,"data":{"key":"K","value":"V"}}`),
+		Error:         "",
+		Values:        1,
+		Received:      ShapeOne,
+		MarshaledBack: `{"status":200,"data":{"key":"K","value":"V"}}`,
+	}, //*/
+
+	{
+		Name:          "Array with one element",
+		Payload:       []byte(`{"status":200,"data":[{"key":"K","value":"V"}]}`),
+		Error:         "",
+		Values:        1,
+		Received:      ShapeMany,
+		MarshaledBack: `{"status":200,"data":[{"key":"K","value":"V"}]}`,
+	}, //*/
+
+	{
+		Name: "Array",
+		Payload: []byte(`{"status":200,"data":[{"key":"K","value":"V"},` +
+			`{"key":"K1","value":"V2"}]}`),
+		Error:    "",
+		Values:   2,
+		Received: ShapeMany,
+		MarshaledBack: `{"status":200,"data":[{"key":"K","value":"V"},` +
+			`{"key":"K1","value":"V2"}]}`,
+	}, //*/
+
+	{
+		Name:          "Null",
+		Payload:       []byte(`{"status":200,"data":null}`),
+		Error:         "",
+		Values:        0,
+		Received:      ShapeUnknown,
+		MarshaledBack: `{"status":200,"data":null}`,
+	}, //*/
+
+	{
+		Name:          "Empty array",
+		Payload:       []byte(`{"status":200,"data":[]}`),
+		Error:         "",
+		Values:        0,
+		Received:      ShapeMany,
+		MarshaledBack: `{"status":200,"data":[]}`,
+	}, //*/
+
+	{
+		Name:          "Object marshaled as many",
+		Payload:       []byte(`{"status":200,"data":{"key":"K","value":"V"}}`),
+		MarshalAs:     ShapeMany,
+		Error:         "",
+		Values:        1,
+		Received:      ShapeOne,
+		MarshaledBack: `{"status":200,"data":[{"key":"K","value":"V"}]}`,
+	}, //*/
+
+	{
+		Name:          "Array with one element marshaled as one",
+		Payload:       []byte(`{"status":200,"data":[{"key":"K","value":"V"}]}`),
+		MarshalAs:     ShapeOne,
+		Error:         "",
+		Values:        1,
+		Received:      ShapeMany,
+		MarshaledBack: `{"status":200,"data":{"key":"K","value":"V"}}`,
+	}, //*/
+
+	{
+		Name: "Array marshaled as one falls back to many",
+		Payload: []byte(`{"status":200,"data":[{"key":"K","value":"V"},` +
+			`{"key":"K1","value":"V2"}]}`),
+		MarshalAs: ShapeOne,
+		Error:     "",
+		Values:    2,
+		Received:  ShapeMany,
+		MarshaledBack: `{"status":200,"data":[{"key":"K","value":"V"},` +
+			`{"key":"K1","value":"V2"}]}`,
+	}, //*/
+
+	{
+		Name:          "Unexpected type",
+		Payload:       []byte(`{"status":200,"data":"V"}`),
+		Error:         "json: cannot unmarshal string into Go value of type jsonutils.Tag",
+		Values:        0,
+		Received:      ShapeUnknown,
+		MarshaledBack: `{"status":200,"data":null}`,
+	}, //*/
+
+	/* Template
+	{
+		Name:          "",
+		Payload:       []byte(`{"status":200,"data": }`),
+		Error:         "",
+		Values:        0,
+		Received:      ShapeUnknown,
+		MarshaledBack: ``,
+	}, //*/
+
+}
+
+func TestOneOrMany_Raw(t *testing.T) {
+	t.Parallel()
+	for i := range OneOrManyTestsRaw {
+		t.Run(OneOrManyTestsRaw[i].Name, func(i int) func(*testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				test := OneOrManyTestsRaw[i]
+				var strErr string
+				r := OneOrManyResponse{}
+				r.Data.MarshalAs = test.MarshalAs
+				err := json.Unmarshal(test.Payload, &r)
+				if err != nil {
+					strErr = err.Error()
+				}
+				if strErr != test.Error {
+					t.Fatalf("Unexpected unmarshal error\nWant Error: %s"+
+						"\n Got Error: %s", test.Error, strErr)
+				}
+				if len(r.Data.Values) != test.Values {
+					t.Fatalf("Unexpected number of values\nWant: %d\n Got: %d",
+						test.Values, len(r.Data.Values))
+				}
+				if r.Data.Received() != test.Received {
+					t.Fatalf("Unexpected shape\nWant: %d\n Got: %d",
+						test.Received, r.Data.Received())
+				}
+				b, err := json.Marshal(r)
+				if err != nil {
+					t.Fatalf("Unexpected error while marshaling: %v", err)
+				}
+				if string(b) != test.MarshaledBack {
+					t.Fatalf("Failed marshaling back\nWant MarshalBack: %s\n"+
+						" Got MarshalBack: %s", test.MarshaledBack, b)
+				}
+			}
+		}(i))
+	}
+}
+
+func TestOneOrMany_Scalars(t *testing.T) {
+	var o OneOrMany[string]
+
+	if err := json.Unmarshal([]byte(`"a"`), &o); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(o.Values) != 1 || o.Values[0] != "a" {
+		t.Fatalf("Want: [a]; Have: %v", o.Values)
+	}
+
+	// Decoding again must not keep stale values.
+	if err := json.Unmarshal([]byte(`["b","c"]`), &o); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(o.Values) != 2 || o.Values[0] != "b" || o.Values[1] != "c" {
+		t.Fatalf("Want: [b c]; Have: %v", o.Values)
+	}
+
+	if err := o.UnmarshalJSON(nil); err != ErrEmpty {
+		t.Fatalf("Want: %v; Have: %v", ErrEmpty, err)
+	}
+
+	// Values built by hand default to an array.
+	b, err := json.Marshal(OneOrMany[string]{Values: []string{"d"}})
+	if err != nil || string(b) != `["d"]` {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", `["d"]`, b, err)
+	}
+	b, err = json.Marshal(OneOrMany[string]{MarshalAs: ShapeMany})
+	if err != nil || string(b) != `[]` {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", `[]`, b, err)
+	}
+}
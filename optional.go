@@ -0,0 +1,70 @@
+package jsonutils
+
+import "encoding/json"
+
+// Optional is a Payload that tells apart the three states of an optional
+// field, which is useful, for example, for PATCH endpoints:
+//	- Absent: the field was not in the JSON Object, so it was never
+//		unmarshaled. This is its zero value.
+//	- Null: the field was explicitly set to JSON Null.
+//	- Present: the field was set to any other value.
+//
+// Unlike a *Payload field, which the JSON Unmarshaler sets to nil when it
+// receives a JSON Null, an Optional field is always unmarshaled. JSON Null is
+// always accepted, and the other JSON Data Types need to be enabled with the
+// With* methods, like with Payload. All the Payload methods are available
+// through embedding.
+//
+// When marshaling, an absent Optional is encoded as JSON Null. Since Go 1.24,
+// it can be left out of the JSON Object altogether by using the "omitzero"
+// option in the field tag, so all three states are preserved:
+//
+//	type UpdateUser struct {
+//		Name  jsonutils.Optional `json:"name,omitzero"`
+//		Email jsonutils.Optional `json:"email,omitzero"`
+//	}
+//
+// Older Go versions ignore that option, so the struct holding the Optional
+// fields needs its own MarshalJSON method that leaves out the fields for which
+// IsAbsent reports true.
+//
+// Like Payload, Optional cannot be copied, so the struct holding it must be
+// marshaled through a pointer.
+type Optional struct {
+	Payload
+}
+
+// Assert at compile-time that we implement the JSON Marshaler and Unmarshaler
+// interfaces.
+var (
+	_ json.Marshaler   = (*Optional)(nil)
+	_ json.Unmarshaler = (*Optional)(nil)
+)
+
+// UnmarshalJSON implements the JSON Unmarshaler interface. It accepts JSON Null
+// even if it was not enabled with WithNull.
+func (o *Optional) UnmarshalJSON(b []byte) error {
+	if !o.with[Null] {
+		o.with[Null] = true
+		defer func() { o.with[Null] = false }()
+	}
+	return o.Payload.UnmarshalJSON(b)
+}
+
+// SetNull sets the value to JSON Null. It never fails.
+func (o *Optional) SetNull() error {
+	return o.Payload.SetNull(true)
+}
+
+// IsAbsent reports whether no value was set, either because it was never
+// unmarshaled or because the last unmarshaling failed. Call Clear to make an
+// Optional absent again.
+func (o *Optional) IsAbsent() bool { return o.mapping == GoInvalidMapping }
+
+// IsPresent reports whether a value was set, including JSON Null. Use IsNil to
+// tell if it was JSON Null.
+func (o *Optional) IsPresent() bool { return !o.IsAbsent() }
+
+// IsZero reports whether the Optional is absent. It's used by the "omitzero"
+// option of encoding/json, available since Go 1.24.
+func (o *Optional) IsZero() bool { return o.IsAbsent() }
@@ -0,0 +1,31 @@
+//go:build go1.24
+
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptional_OmitZero(t *testing.T) {
+	for _, in := range []string{
+		`{}`,
+		`{"name":null}`,
+		`{"name":"N","email":null,"age":42}`,
+		`{"email":"E","age":null}`,
+	} {
+		u := newOptionalTestUpdate()
+		if err := json.Unmarshal([]byte(in), u); err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", in, err)
+		}
+
+		// All three states are preserved when marshaling back.
+		b, err := json.Marshal(u)
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", in, err)
+		}
+		if string(b) != in {
+			t.Fatalf("Want: %s; Have: %s", in, b)
+		}
+	}
+}
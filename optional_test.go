@@ -0,0 +1,117 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type optionalTestUpdate struct {
+	Name  Optional `json:"name,omitzero"`
+	Email Optional `json:"email,omitzero"`
+	Age   Optional `json:"age,omitzero"`
+}
+
+func newOptionalTestUpdate() *optionalTestUpdate {
+	u := new(optionalTestUpdate)
+	u.Name.WithString()
+	u.Email.WithString()
+	u.Age.WithInt()
+	return u
+}
+
+func TestOptional(t *testing.T) {
+	tests := []struct {
+		JSONData               string
+		NameAbsent, NameNull   bool
+		EmailAbsent, EmailNull bool
+		AgeAbsent, AgeNull     bool
+		Name, Email, Age       interface{}
+	}{
+		{JSONData: `{}`, NameAbsent: true, EmailAbsent: true,
+			AgeAbsent: true},
+		{JSONData: `{"name":null}`, NameNull: true, EmailAbsent: true,
+			AgeAbsent: true},
+		{JSONData: `{"name":"N","email":null,"age":42}`, Name: "N",
+			EmailNull: true, Age: int64(42)},
+		{JSONData: `{"email":"E","age":null}`, NameAbsent: true,
+			Email: "E", AgeNull: true},
+	}
+
+	for _, test := range tests {
+		u := newOptionalTestUpdate()
+		if err := json.Unmarshal([]byte(test.JSONData), u); err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.JSONData, err)
+		}
+
+		for _, c := range []struct {
+			Name           string
+			O              *Optional
+			Absent, IsNull bool
+		}{
+			{"name", &u.Name, test.NameAbsent, test.NameNull},
+			{"email", &u.Email, test.EmailAbsent, test.EmailNull},
+			{"age", &u.Age, test.AgeAbsent, test.AgeNull},
+		} {
+			if c.O.IsAbsent() != c.Absent || c.O.IsPresent() == c.Absent ||
+				c.O.IsZero() != c.Absent || c.O.IsNil() != c.IsNull {
+				t.Fatalf("[%s] Unexpected state for %s: %v", test.JSONData,
+					c.Name, c.O.GetMapping())
+			}
+		}
+		name, _ := u.Name.Get()
+		email, _ := u.Email.Get()
+		age, _ := u.Age.Get()
+		if name != test.Name || email != test.Email || age != test.Age {
+			t.Fatalf("[%s] Unexpected values: %v, %v, %v", test.JSONData,
+				name, email, age)
+		}
+
+		// Absent values are marshaled as JSON Null, unless left out with
+		// "omitzero" (see TestOptional_OmitZero).
+		for _, c := range []struct {
+			O    *Optional
+			Want interface{}
+		}{{&u.Name, test.Name}, {&u.Email, test.Email}, {&u.Age, test.Age}} {
+			want, _ := json.Marshal(c.Want)
+			if b, err := json.Marshal(c.O); err != nil ||
+				string(b) != string(want) {
+				t.Fatalf("[%s] Want: %s; Have: %s (error: %v)",
+					test.JSONData, want, b, err)
+			}
+		}
+	}
+}
+
+func TestOptional_Errors(t *testing.T) {
+	u := newOptionalTestUpdate()
+	err := json.Unmarshal([]byte(`{"age":"42"}`), u)
+	if !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+	if !u.Age.IsAbsent() {
+		t.Fatalf("Unexpected state: %v", u.Age.GetMapping())
+	}
+
+	// JSON Null is accepted without changing the configuration.
+	var o Optional
+	if err = o.UnmarshalJSON([]byte(`null`)); err != nil || !o.IsNil() {
+		t.Fatalf("Unexpected result: %v (error: %v)", o.GetMapping(), err)
+	}
+	if o.with[Null] {
+		t.Fatalf("Configuration was modified")
+	}
+	if err = o.UnmarshalJSON([]byte(`true`)); !errors.Is(err,
+		ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+
+	// Setters.
+	if err = o.SetNull(); err != nil || !o.IsNil() {
+		t.Fatalf("Unexpected result: %v (error: %v)", o.GetMapping(), err)
+	}
+	o.Clear()
+	if !o.IsAbsent() {
+		t.Fatalf("Unexpected state: %v", o.GetMapping())
+	}
+}
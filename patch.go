@@ -0,0 +1,495 @@
+package jsonutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// PatchError describes a JSON Patch operation that could not be applied,
+// identified by its position in the patch and its target location.
+type PatchError struct {
+	// Index is the position of the operation in the JSON Patch document.
+	Index int
+	// Op is the name of the operation, like "add" or "test".
+	Op string
+	// Path is the target location of the operation.
+	Path string
+	// Err is the underlying error, like ErrTestFailed or a *PointerError.
+	Err error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s %q): %v", e.Index, e.Op, e.Path,
+		e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PatchError) Unwrap() error { return e.Err }
+
+// patchOp is an operation of a JSON Patch document.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyPatch applies the JSON Patch (RFC 6902) document patch to the JSON
+// document doc, and returns the patched document. It supports the add, remove,
+// replace, move, copy and test operations.
+//
+// The patch is applied with all-or-nothing semantics: if any operation fails,
+// then no document is returned. JSON Numbers keep their exact text, and are
+// compared by their numeric value in the test operation. Note that the
+// returned document is encoded again, so its formatting and the order of the
+// Object members are not preserved.
+//
+// It returns a *PatchError if an operation could not be applied, and an error
+// matching ErrInvalidPatch if the patch is malformed.
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	var ops []patchOp
+	if t, err := TypeOf(patch); err != nil || t != Array {
+		return nil, fmt.Errorf("%w: not an array", ErrInvalidPatch)
+	}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	root, err := decodeTree(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if root, err = applyOp(root, op); err != nil {
+			e := &PatchError{Index: i, Op: op.Op, Err: err}
+			if op.Path != nil {
+				e.Path = *op.Path
+			}
+			return nil, e
+		}
+	}
+
+	return encodeTree(root)
+}
+
+// applyOp applies a single operation to the decoded JSON document root, and
+// returns the new root.
+func applyOp(root interface{}, op patchOp) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidPatch)
+	}
+	path, err := ParsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	var from Pointer
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		if value, err = decodeTree(op.Value); err != nil {
+			return nil, err
+		}
+
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+		if from, err = ParsePointer(*op.From); err != nil {
+			return nil, err
+		}
+		if value, err = from.GetValue(root); err != nil {
+			return nil, err
+		}
+
+	case "remove":
+
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch,
+			op.Op)
+	}
+
+	switch op.Op {
+	case "add":
+		return addValue(root, path, value)
+
+	case "remove":
+		return removeValue(root, path)
+
+	case "replace":
+		if _, err = path.GetValue(root); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return updateValue(root, path, 0, func(c interface{}, i int) (
+			interface{}, error) {
+			setChild(c, path[i], value)
+			return c, nil
+		})
+
+	case "move":
+		if isPrefix(from, path) {
+			if len(from) == len(path) {
+				return root, nil
+			}
+			return nil, fmt.Errorf("%w: cannot move a value into itself",
+				ErrInvalidPatch)
+		}
+		if root, err = removeValue(root, from); err != nil {
+			return nil, err
+		}
+		return addValue(root, path, value)
+
+	case "copy":
+		return addValue(root, path, copyTree(value))
+	}
+
+	// test
+	current, err := path.GetValue(root)
+	if err != nil {
+		return nil, err
+	}
+	if !equalTrees(current, value) {
+		return nil, ErrTestFailed
+	}
+	return root, nil
+}
+
+// addValue implements the add operation.
+func addValue(root interface{}, path Pointer, v interface{}) (interface{},
+	error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	return updateValue(root, path, 0, func(c interface{}, i int) (interface{},
+		error) {
+		arr, ok := c.([]interface{})
+		if !ok {
+			c.(map[string]interface{})[path[i]] = v
+			return c, nil
+		}
+
+		idx := len(arr)
+		if path[i] != "-" {
+			var err error
+			if idx, err = path.index(i, len(arr)+1); err != nil {
+				return nil, err
+			}
+		}
+		arr = append(arr, nil)
+		copy(arr[idx+1:], arr[idx:])
+		arr[idx] = v
+		return arr, nil
+	})
+}
+
+// removeValue implements the remove operation.
+func removeValue(root interface{}, path Pointer) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document",
+			ErrInvalidPatch)
+	}
+	return updateValue(root, path, 0, func(c interface{}, i int) (interface{},
+		error) {
+		if obj, ok := c.(map[string]interface{}); ok {
+			if _, ok = obj[path[i]]; !ok {
+				return nil, path.errorAt(i, ErrNotFound)
+			}
+			delete(obj, path[i])
+			return obj, nil
+		}
+
+		arr := c.([]interface{})
+		idx, err := path.index(i, len(arr))
+		if err != nil {
+			return nil, err
+		}
+		return append(arr[:idx], arr[idx+1:]...), nil
+	})
+}
+
+// updateValue calls fn with the Object or Array that holds the value
+// referenced by path within node, and the position of the last reference token
+// of path. It returns node with the container replaced by the one returned by
+// fn. The path must not be empty.
+func updateValue(node interface{}, path Pointer, i int,
+	fn func(c interface{}, i int) (interface{}, error)) (interface{}, error) {
+	var child interface{}
+	switch x := node.(type) {
+	case map[string]interface{}:
+		if i == len(path)-1 {
+			return fn(x, i)
+		}
+		var ok bool
+		if child, ok = x[path[i]]; !ok {
+			return nil, path.errorAt(i, ErrNotFound)
+		}
+
+	case []interface{}:
+		if i == len(path)-1 {
+			return fn(x, i)
+		}
+		idx, err := path.index(i, len(x))
+		if err != nil {
+			return nil, err
+		}
+		child = x[idx]
+
+	default:
+		return nil, path.errorAt(i, &UnexpectedTypeError{Got: valueType(node),
+			Allowed: []JSONType{Object, Array}})
+	}
+
+	child, err := updateValue(child, path, i+1, fn)
+	if err != nil {
+		return nil, err
+	}
+	setChild(node, path[i], child)
+	return node, nil
+}
+
+// setChild sets the member or element of the Object or Array c referenced by
+// tok, which must already exist.
+func setChild(c interface{}, tok string, v interface{}) {
+	if obj, ok := c.(map[string]interface{}); ok {
+		obj[tok] = v
+		return
+	}
+	idx, _ := strconv.Atoi(tok)
+	c.([]interface{})[idx] = v
+}
+
+// isPrefix reports whether p is a prefix of q.
+func isPrefix(p, q Pointer) bool {
+	if len(p) > len(q) {
+		return false
+	}
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CreatePatch returns a JSON Patch (RFC 6902) document that transforms the
+// JSON document a into b when applied with ApplyPatch. The patch only has add,
+// remove and replace operations, targeting the deepest values that differ.
+//
+// JSON Numbers are compared by their numeric value, and keep their exact text
+// in the patch.
+func CreatePatch(a, b []byte) ([]byte, error) {
+	treeA, err := decodeTree(a)
+	if err != nil {
+		return nil, err
+	}
+	treeB, err := decodeTree(b)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := diffTrees(nil, Pointer{}, treeA, treeB)
+	if ops == nil {
+		ops = []patchOp{}
+	}
+	return encodeTree(ops)
+}
+
+// diffTrees appends to ops the operations that transform a into b, both at
+// path.
+func diffTrees(ops []patchOp, path Pointer, a, b interface{}) []patchOp {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			return diffObjects(ops, path, x, y)
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			return diffArrays(ops, path, x, y)
+		}
+	}
+
+	if equalTrees(a, b) {
+		return ops
+	}
+	return append(ops, newPatchOp("replace", path, b))
+}
+
+func diffObjects(ops []patchOp, path Pointer, a,
+	b map[string]interface{}) []patchOp {
+	for _, k := range sortedKeys(a) {
+		if _, ok := b[k]; !ok {
+			ops = append(ops, newPatchOp("remove", childPath(path, k), nil))
+		}
+	}
+	for _, k := range sortedKeys(b) {
+		if va, ok := a[k]; ok {
+			ops = diffTrees(ops, childPath(path, k), va, b[k])
+		} else {
+			ops = append(ops, newPatchOp("add", childPath(path, k), b[k]))
+		}
+	}
+	return ops
+}
+
+// diffArrays skips the elements that are equal at the start and the end of
+// both Arrays, and then compares the remaining elements by position.
+func diffArrays(ops []patchOp, path Pointer, a, b []interface{}) []patchOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && equalTrees(a[prefix],
+		b[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		equalTrees(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+
+	midA, midB := len(a)-prefix-suffix, len(b)-prefix-suffix
+	common := midA
+	if midB < common {
+		common = midB
+	}
+	for i := prefix; i < prefix+common; i++ {
+		ops = diffTrees(ops, childPath(path, strconv.Itoa(i)), a[i], b[i])
+	}
+	// Remove from the end so the indexes don't shift.
+	for i := prefix + midA - 1; i >= prefix+common; i-- {
+		ops = append(ops, newPatchOp("remove", childPath(path,
+			strconv.Itoa(i)), nil))
+	}
+	for i := prefix + common; i < prefix+midB; i++ {
+		ops = append(ops, newPatchOp("add", childPath(path, strconv.Itoa(i)),
+			b[i]))
+	}
+	return ops
+}
+
+func newPatchOp(op string, path Pointer, v interface{}) patchOp {
+	s := path.String()
+	ret := patchOp{Op: op, Path: &s}
+	if op != "remove" {
+		ret.Value, _ = encodeTree(v)
+	}
+	return ret
+}
+
+// childPath returns a new Pointer that references the child tok of path.
+func childPath(path Pointer, tok string) Pointer {
+	return append(path[:len(path):len(path)], tok)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeTree decodes the JSON document b into an interface{}, using
+// json.Number for the JSON Numbers. It fails if there is anything else than
+// whitespace after the value.
+func decodeTree(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			err = ErrEmpty
+		}
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		i := skipSpace(b, int(dec.InputOffset()))
+		return nil, newSyntaxError(b, i, ErrInvalidJSON)
+	}
+	return v, nil
+}
+
+// encodeTree encodes v without escaping HTML characters.
+func encodeTree(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// copyTree returns a deep copy of the decoded JSON value v.
+func copyTree(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			ret[k] = copyTree(v)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(x))
+		for i, v := range x {
+			ret[i] = copyTree(v)
+		}
+		return ret
+	}
+	return v
+}
+
+// equalTrees reports whether the decoded JSON values a and b are equal. JSON
+// Numbers are compared by their numeric value.
+func equalTrees(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !equalTrees(v, w) {
+				return false
+			}
+		}
+		return true
+
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalTrees(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		rx, okx := new(big.Rat).SetString(string(x))
+		ry, oky := new(big.Rat).SetString(string(y))
+		return okx && oky && rx.Cmp(ry) == 0
+	}
+
+	return a == b
+}
@@ -0,0 +1,275 @@
+package jsonutils
+
+import (
+	"errors"
+	"testing"
+)
+
+// PatchTestsRaw are mostly the examples from RFC 6902 appendix A.
+var PatchTestsRaw = []struct {
+	Name  string
+	Doc   string
+	Patch string
+	Want  string
+	Error error
+}{
+
+	{
+		Name:  "Adding an Object Member",
+		Doc:   `{"foo":"bar"}`,
+		Patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+		Want:  `{"baz":"qux","foo":"bar"}`,
+	}, //*/
+
+	{
+		Name:  "Adding an Array Element",
+		Doc:   `{"foo":["bar","baz"]}`,
+		Patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+		Want:  `{"foo":["bar","qux","baz"]}`,
+	}, //*/
+
+	{
+		Name:  "Removing an Object Member",
+		Doc:   `{"baz":"qux","foo":"bar"}`,
+		Patch: `[{"op":"remove","path":"/baz"}]`,
+		Want:  `{"foo":"bar"}`,
+	}, //*/
+
+	{
+		Name:  "Removing an Array Element",
+		Doc:   `{"foo":["bar","qux","baz"]}`,
+		Patch: `[{"op":"remove","path":"/foo/1"}]`,
+		Want:  `{"foo":["bar","baz"]}`,
+	}, //*/
+
+	{
+		Name:  "Replacing a Value",
+		Doc:   `{"baz":"qux","foo":"bar"}`,
+		Patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+		Want:  `{"baz":"boo","foo":"bar"}`,
+	}, //*/
+
+	{
+		Name: "Moving a Value",
+		Doc: `{"foo":{"bar":"baz","waldo":"fred"},` +
+			`"qux":{"corge":"grault"}}`,
+		Patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+		Want: `{"foo":{"bar":"baz"},` +
+			`"qux":{"corge":"grault","thud":"fred"}}`,
+	}, //*/
+
+	{
+		Name:  "Moving an Array Element",
+		Doc:   `{"foo":["all","grass","cows","eat"]}`,
+		Patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+		Want:  `{"foo":["all","cows","eat","grass"]}`,
+	}, //*/
+
+	{
+		Name: "Testing a Value: Success",
+		Doc:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		Patch: `[{"op":"test","path":"/baz","value":"qux"},` +
+			`{"op":"test","path":"/foo/1","value":2}]`,
+		Want: `{"baz":"qux","foo":["a",2,"c"]}`,
+	}, //*/
+
+	{
+		Name:  "Testing a Value: Error",
+		Doc:   `{"baz":"qux"}`,
+		Patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+		Error: ErrTestFailed,
+	}, //*/
+
+	{
+		Name:  "Adding a Nested Member Object",
+		Doc:   `{"foo":"bar"}`,
+		Patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+		Want:  `{"child":{"grandchild":{}},"foo":"bar"}`,
+	}, //*/
+
+	{
+		Name: "Ignoring Unrecognized Elements",
+		Doc:  `{"foo":"bar"}`,
+		Patch: `[{"op":"add","path":"/baz","value":"qux",` +
+			`"xyz":123}]`,
+		Want: `{"baz":"qux","foo":"bar"}`,
+	}, //*/
+
+	{
+		Name:  "Adding to a Nonexistent Target",
+		Doc:   `{"foo":"bar"}`,
+		Patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+		Error: ErrNotFound,
+	}, //*/
+
+	{
+		// Like encoding/json, the last duplicate member is used.
+		Name:  "Invalid JSON Patch Document",
+		Doc:   `{"foo":"bar"}`,
+		Patch: `[{"op":"add","path":"/baz","value":"qux","op":"remove"}]`,
+		Error: ErrNotFound,
+	}, //*/
+
+	{
+		Name:  "~ Escape Ordering",
+		Doc:   `{"/":9,"~1":10}`,
+		Patch: `[{"op":"test","path":"/~01","value":10}]`,
+		Want:  `{"/":9,"~1":10}`,
+	}, //*/
+
+	{
+		Name:  "Comparing Strings and Numbers",
+		Doc:   `{"/":9,"~1":10}`,
+		Patch: `[{"op":"test","path":"/~01","value":"10"}]`,
+		Error: ErrTestFailed,
+	}, //*/
+
+	{
+		Name:  "Adding an Array Value",
+		Doc:   `{"foo":["bar"]}`,
+		Patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+		Want:  `{"foo":["bar",["abc","def"]]}`,
+	}, //*/
+
+	{
+		Name: "Exact numbers",
+		Doc:  `{"id":123456789012345678901234567890,"price":0.1}`,
+		Patch: `[{"op":"test","path":"/price","value":1e-1},` +
+			`{"op":"copy","from":"/id","path":"/copy"}]`,
+		Want: `{"copy":123456789012345678901234567890,` +
+			`"id":123456789012345678901234567890,"price":0.1}`,
+	}, //*/
+
+	{
+		Name:  "No HTML escaping",
+		Doc:   `{}`,
+		Patch: `[{"op":"add","path":"/html","value":"<b>&</b>"}]`,
+		Want:  `{"html":"<b>&</b>"}`,
+	}, //*/
+
+	{
+		Name:  "Replace the whole document",
+		Doc:   `{"a":1}`,
+		Patch: `[{"op":"replace","path":"","value":[1]}]`,
+		Want:  `[1]`,
+	}, //*/
+
+	{
+		Name: "All or nothing",
+		Doc:  `{"a":1}`,
+		Patch: `[{"op":"remove","path":"/a"},` +
+			`{"op":"replace","path":"/a","value":2}]`,
+		Error: ErrNotFound,
+	}, //*/
+
+	{
+		Name:  "Moving a value into itself",
+		Doc:   `{"a":{"b":1}}`,
+		Patch: `[{"op":"move","from":"/a","path":"/a/c"}]`,
+		Error: ErrInvalidPatch,
+	}, //*/
+
+	{
+		Name:  "Unknown operation",
+		Doc:   `{}`,
+		Patch: `[{"op":"merge","path":""}]`,
+		Error: ErrInvalidPatch,
+	}, //*/
+
+	{
+		Name:  "Missing value",
+		Doc:   `{}`,
+		Patch: `[{"op":"add","path":"/a"}]`,
+		Error: ErrInvalidPatch,
+	}, //*/
+
+	{
+		Name:  "Not a patch",
+		Doc:   `{}`,
+		Patch: `{"op":"add","path":"/a","value":1}`,
+		Error: ErrInvalidPatch,
+	}, //*/
+}
+
+func TestApplyPatch(t *testing.T) {
+	for _, test := range PatchTestsRaw {
+		have, err := ApplyPatch([]byte(test.Doc), []byte(test.Patch))
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Name, test.Error,
+				err)
+		}
+		if string(have) != test.Want {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.Name, test.Want, have)
+		}
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	patch := `[{"op":"test","path":"/a","value":1},` +
+		`{"op":"remove","path":"/b/c"}]`
+	_, err := ApplyPatch([]byte(`{"a":1,"b":[]}`), []byte(patch))
+
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || patchErr.Index != 1 ||
+		patchErr.Op != "remove" || patchErr.Path != "/b/c" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `patch operation 1 (remove "/b/c"): pointer "/b/c": token "c": ` +
+		`invalid array index`
+	if err.Error() != want {
+		t.Fatalf("Want: %s; Have: %s", want, err)
+	}
+
+	// Trailing data is reported at the offending byte.
+	_, err = ApplyPatch([]byte(`{"a":1} x`), []byte(`[]`))
+	var syntaxErr *SyntaxError
+	if !errors.Is(err, ErrInvalidJSON) || !errors.As(err, &syntaxErr) ||
+		syntaxErr.Offset != 8 || syntaxErr.Char != 'x' {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		A, B string
+		Want string
+	}{
+		{`{"a":1}`, `{"a":1.0}`, `[]`},
+		{`{"a":1,"b":2}`, `{"b":3,"c":4}`,
+			`[{"op":"remove","path":"/a"},` +
+				`{"op":"replace","path":"/b","value":3},` +
+				`{"op":"add","path":"/c","value":4}]`},
+		{`[1,2,3,4]`, `[1,5,4]`,
+			`[{"op":"replace","path":"/1","value":5},` +
+				`{"op":"remove","path":"/2"}]`},
+		{`[1,4]`, `[1,2,3,4]`,
+			`[{"op":"add","path":"/1","value":2},` +
+				`{"op":"add","path":"/2","value":3}]`},
+		{`{"a/b":{"c":[1]}}`, `{"a/b":{"c":[1,{"d":"<"}]}}`,
+			`[{"op":"add","path":"/a~1b/c/1","value":{"d":"<"}}]`},
+		{`{"a":1}`, `[1]`, `[{"op":"replace","path":"","value":[1]}]`},
+		{`1`, `100000000000000000000000000001`,
+			`[{"op":"replace","path":"",` +
+				`"value":100000000000000000000000000001}]`},
+	}
+
+	for _, test := range tests {
+		patch, err := CreatePatch([]byte(test.A), []byte(test.B))
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.A, err)
+		}
+		if string(patch) != test.Want {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.A, test.Want, patch)
+		}
+
+		// Applying the patch transforms A into B.
+		have, err := ApplyPatch([]byte(test.A), patch)
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.A, err)
+		}
+		want, _ := decodeTree([]byte(test.B))
+		if got, _ := decodeTree(have); !equalTrees(got, want) {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.A, test.B, have)
+		}
+	}
+}
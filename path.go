@@ -0,0 +1,445 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathError describes a path that could not be resolved by GetPath, SetPath
+// or DeletePath, pointing at the component where resolving it stopped.
+type PathError struct {
+	// Path is the path that failed.
+	Path string
+	// Index is the position in Path of the component that failed, counting
+	// components from zero.
+	Index int
+	// Component is the component that failed, already unescaped.
+	Component string
+	// Err is the underlying error, like ErrNotFound or ErrInvalidIndex.
+	Err error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("path %q: component %q: %v", e.Path, e.Component,
+		e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error { return e.Err }
+
+// pathComponent is a component of a path, as used by GetPath, SetPath and
+// DeletePath.
+type pathComponent struct {
+	name string
+	all  bool // The '#' component
+}
+
+// parsePath splits path into its components.
+func parsePath(path string) ([]pathComponent, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	var ret []pathComponent
+	var sb strings.Builder
+	escaped := false
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '\\':
+			if i++; i == len(path) {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			sb.WriteByte(path[i])
+			escaped = true
+		case '.':
+			ret = append(ret, newPathComponent(sb.String(), escaped))
+			sb.Reset()
+			escaped = false
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return append(ret, newPathComponent(sb.String(), escaped)), nil
+}
+
+func newPathComponent(name string, escaped bool) pathComponent {
+	return pathComponent{name: name, all: name == "#" && !escaped}
+}
+
+// GetPath returns the bytes of the value found at path within the JSON
+// document doc, and its JSON Data Type. It scans doc once, only as far as
+// needed, without decoding it and without reflection, so the result can be
+// passed straight to a Payload or any other JSON Unmarshaler.
+//
+// The path is a series of components separated by dots, like
+// "data.users.0.email":
+//	- A component selects the member of an Object with that name, or the
+//		element of an Array with that index.
+//	- The '#' component, when applied to an Array, returns its number of
+//		elements if it's the last component. Otherwise, the rest of the path
+//		is applied to each element, and the results are returned as a new JSON
+//		Array, skipping the elements where the rest of the path was not found.
+//		For example, "data.users.#.email" returns an Array with the emails of
+//		all the users.
+//	- The "-" component, like in JSON Pointer, refers to the element after
+//		the last one of an Array, so it's never found. It's meant for SetPath.
+//	- A backslash escapes the next character, so "a\.b" selects the member
+//		named "a.b", and "\#" the member named "#".
+//	- The empty path selects the whole document.
+//
+// Unless '#' is used, the bytes returned are a subslice of doc. If an Object
+// has duplicate member names, then the first member is used.
+//
+// It returns a *SyntaxError if the scanned parts of doc are not valid, an
+// error matching ErrInvalidPath if path is malformed, and a *PathError if
+// path could not be resolved.
+func GetPath(doc []byte, path string) ([]byte, JSONType, error) {
+	g, start, end, err := newPathGetter(doc, path)
+	if err != nil {
+		return nil, InvalidJSON, err
+	}
+	return g.get(start, end, 0)
+}
+
+// pathGetter resolves the components of a path within doc.
+type pathGetter struct {
+	doc   []byte
+	path  string
+	comps []pathComponent
+}
+
+// get resolves the components from the position i within the value in
+// doc[start:end].
+func (g *pathGetter) get(start, end, i int) ([]byte, JSONType, error) {
+	for ; i < len(g.comps); i++ {
+		if g.comps[i].all && g.doc[start] == '[' {
+			return g.getAll(start, end, i)
+		}
+
+		var found bool
+		var err error
+		start, end, found, err = g.step(start, end, i)
+		if err != nil {
+			return nil, InvalidJSON, err
+		}
+		if !found {
+			return nil, InvalidJSON, g.errorAt(i, ErrNotFound)
+		}
+	}
+
+	t, _ := TypeOf(g.doc[start:end])
+	return g.doc[start:end], t, nil
+}
+
+// step resolves the component at position i within the JSON Object or Array
+// in doc[start:end], and returns the positions of the value found. If it's not
+// found, then the positions of the Object or Array are returned unchanged.
+func (g *pathGetter) step(start, end, i int) (int, int, bool, error) {
+	doc, comp := g.doc, g.comps[i]
+	switch doc[start] {
+	case '{':
+		return memberSpan(doc, start, end, i+1, comp.name)
+
+	case '[':
+		if comp.name == "-" {
+			// The element after the last one, like in JSON Pointer.
+			return start, end, false, nil
+		}
+		idx, ok := parseIndex(comp.name)
+		if !ok || comp.all {
+			return start, end, false, g.errorAt(i, ErrInvalidIndex)
+		}
+		return elementSpan(doc, start, end, i+1, idx)
+	}
+
+	t, _ := TypeOf(doc[start:end])
+	return start, end, false, g.errorAt(i, &UnexpectedTypeError{Got: t,
+		Allowed: []JSONType{Object, Array}, Offset: int64(start)})
+}
+
+// getAll resolves the '#' component at position i for the JSON Array in
+// doc[start:end].
+func (g *pathGetter) getAll(start, end, i int) ([]byte, JSONType, error) {
+	if i == len(g.comps)-1 {
+		n, err := arrayLen(g.doc, start, end, i+1)
+		if err != nil {
+			return nil, InvalidJSON, err
+		}
+		return strconv.AppendInt(nil, int64(n), 10), Number, nil
+	}
+
+	ret := []byte{'['}
+	var elemErr error
+	_, err := eachElement(g.doc[:end], start, i+1, func(s, e int) bool {
+		v, _, getErr := g.get(s, e, i+1)
+		if _, ok := getErr.(*PathError); ok {
+			// Skip the elements where the path was not found.
+			return true
+		}
+		if getErr != nil {
+			elemErr = getErr
+			return false
+		}
+		if len(ret) > 1 {
+			ret = append(ret, ',')
+		}
+		ret = append(ret, v...)
+		return true
+	})
+	if elemErr != nil {
+		err = elemErr
+	}
+	if err != nil {
+		return nil, InvalidJSON, err
+	}
+	return append(ret, ']'), Array, nil
+}
+
+// errorAt returns a *PathError for the component at position i.
+func (g *pathGetter) errorAt(i int, err error) error {
+	return &PathError{Path: g.path, Index: i, Component: g.comps[i].name,
+		Err: err}
+}
+
+// SetPath returns a copy of the JSON document doc with the value at path set
+// to the JSON encoding of value, using the same path syntax as GetPath. If
+// value is a json.RawMessage, then it's used as is after being validated.
+//
+// Only the bytes of the replaced value are changed, while the rest of doc,
+// including its formatting, the order of the members and the text of the
+// Numbers, is copied unchanged:
+//	- If the last component is not a member of its Object, then the member
+//		is appended to the Object.
+//	- If the last component is "-" or the number of elements of its Array,
+//		then the value is appended to the Array.
+//	- If an intermediate component is not found and create is true, then
+//		it's created as well, with an empty Array if the next component is
+//		"-" or "0", and with an empty Object otherwise. It's not created by
+//		default.
+//	- The '#' component is not supported, and the empty path replaces the
+//		whole document.
+//
+// The errors are the same as for GetPath.
+func SetPath(doc []byte, path string, value interface{},
+	create ...bool) ([]byte, error) {
+	raw, err := encodeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	g, start, end, err := newPathGetter(doc, path)
+	if err != nil {
+		return nil, err
+	}
+
+	i, start, end, err := g.span(start, end, len(g.comps))
+	if err != nil {
+		return nil, err
+	}
+	if i == len(g.comps) {
+		return splice(doc, start, end, raw), nil
+	}
+
+	if i < len(g.comps)-1 && (len(create) == 0 || !create[0]) {
+		return nil, g.errorAt(i, ErrNotFound)
+	}
+	if raw, err = g.build(i+1, raw); err != nil {
+		return nil, err
+	}
+
+	comp := g.comps[i]
+	if doc[start] == '{' {
+		name, _ := encodeTree(comp.name)
+		return g.insert(start, end, name, []byte{':'}, raw), nil
+	}
+	if comp.name != "-" {
+		idx, _ := parseIndex(comp.name)
+		if n, err := arrayLen(doc, start, end, i+1); err != nil {
+			return nil, err
+		} else if idx != n {
+			return nil, g.errorAt(i, ErrNotFound)
+		}
+	}
+	return g.insert(start, end, raw), nil
+}
+
+// DeletePath returns a copy of the JSON document doc without the value at
+// path, using the same path syntax as GetPath. If the value is a member of an
+// Object then the whole member is removed, and if it's an element of an Array
+// then the following elements are shifted.
+//
+// Only the bytes of the removed value, its member name and its separator are
+// removed, while the rest of doc is copied unchanged. The '#' component is not
+// supported, and the empty path is invalid. The errors are the same as for
+// GetPath.
+func DeletePath(doc []byte, path string) ([]byte, error) {
+	g, start, end, err := newPathGetter(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.comps) == 0 {
+		return nil, fmt.Errorf("%w: cannot delete the whole document",
+			ErrInvalidPath)
+	}
+
+	last := len(g.comps) - 1
+	i, start, end, err := g.span(start, end, last)
+	if err != nil {
+		return nil, err
+	}
+	if i < last {
+		return nil, g.errorAt(i, ErrNotFound)
+	}
+
+	// Find the value along with the end of the previous one and the start of
+	// the next one, so that its separator can also be removed.
+	prevEnd, delStart, delEnd, nextStart := -1, -1, -1, -1
+	visit := func(s, e int, match bool) bool {
+		switch {
+		case delStart >= 0:
+			nextStart = s
+			return false
+		case match:
+			delStart, delEnd = s, e
+		default:
+			prevEnd = e
+		}
+		return true
+	}
+
+	comp := g.comps[last]
+	switch doc[start] {
+	case '{':
+		_, err = eachMember(doc[:end], start, last+1,
+			func(ks, ke, _, ve int) bool {
+				return visit(ks, ve, keyEquals(doc[ks:ke], comp.name))
+			})
+
+	case '[':
+		if comp.name == "-" {
+			// The element after the last one, as in step.
+			return nil, g.errorAt(last, ErrNotFound)
+		}
+		idx, ok := parseIndex(comp.name)
+		if !ok || comp.all {
+			return nil, g.errorAt(last, ErrInvalidIndex)
+		}
+		_, err = eachElement(doc[:end], start, last+1, func(s, e int) bool {
+			idx--
+			return visit(s, e, idx == -1)
+		})
+
+	default:
+		_, _, _, err = g.step(start, end, last)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case delStart < 0:
+		return nil, g.errorAt(last, ErrNotFound)
+	case nextStart >= 0:
+		return splice(doc, delStart, nextStart), nil
+	case prevEnd >= 0:
+		return splice(doc, prevEnd, delEnd), nil
+	}
+	return splice(doc, delStart, delEnd), nil
+}
+
+// newPathGetter parses path and returns a pathGetter for it, along with the
+// positions of the value in doc. If path is empty, then that value is the
+// whole doc, so it's fully validated.
+func newPathGetter(doc []byte, path string) (*pathGetter, int, int, error) {
+	comps, err := parsePath(path)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if len(comps) == 0 {
+		if _, err = TypeOfStrict(doc); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	start := skipSpace(doc, 0)
+	end := start + len(trimSpace(doc[start:]))
+	if start == end {
+		return nil, 0, 0, unexpectedAt(doc, start)
+	}
+	return &pathGetter{doc: doc, path: path, comps: comps}, start, end, nil
+}
+
+// span resolves the components before the position n within the value in
+// doc[start:end], without supporting '#'. It returns the position of the
+// first component that was not found, or n, and the positions of the last
+// value found.
+func (g *pathGetter) span(start, end, n int) (int, int, int, error) {
+	for i := 0; i < n; i++ {
+		s, e, found, err := g.step(start, end, i)
+		if err != nil || !found {
+			return i, start, end, err
+		}
+		start, end = s, e
+	}
+	return n, start, end, nil
+}
+
+// build returns raw nested in the containers created for the components from
+// the position i, as done by SetPath.
+func (g *pathGetter) build(i int, raw []byte) ([]byte, error) {
+	for j := len(g.comps) - 1; j >= i; j-- {
+		name := g.comps[j].name
+		if name == "-" || name == "0" {
+			raw = append(append([]byte{'['}, raw...), ']')
+			continue
+		}
+		if _, ok := parseIndex(name); ok {
+			// Arrays cannot have gaps.
+			return nil, g.errorAt(j, ErrNotFound)
+		}
+		key, _ := encodeTree(name)
+		raw = append(append(append(append([]byte{'{'}, key...), ':'),
+			raw...), '}')
+	}
+	return raw, nil
+}
+
+// insert returns a copy of doc with parts appended to the JSON Object or Array
+// in doc[start:end], after a comma if it's not empty.
+func (g *pathGetter) insert(start, end int, parts ...[]byte) []byte {
+	i := end - 1
+	for i > start+1 && isSpace(g.doc[i-1]) {
+		i--
+	}
+	if i > start+1 {
+		parts = append([][]byte{{','}}, parts...)
+	}
+	return splice(g.doc, i, i, parts...)
+}
+
+// splice returns a copy of doc with doc[start:end] replaced by parts.
+func splice(doc []byte, start, end int, parts ...[]byte) []byte {
+	n := len(doc) - (end - start)
+	for _, p := range parts {
+		n += len(p)
+	}
+	ret := append(make([]byte, 0, n), doc[:start]...)
+	for _, p := range parts {
+		ret = append(ret, p...)
+	}
+	return append(ret, doc[end:]...)
+}
+
+// encodeValue returns the JSON encoding of v, or v itself if it's a valid
+// json.RawMessage.
+func encodeValue(v interface{}) ([]byte, error) {
+	if raw, ok := v.(json.RawMessage); ok {
+		if _, err := TypeOfStrict(raw); err != nil {
+			return nil, err
+		}
+		return trimSpace(raw), nil
+	}
+	return encodeTree(v)
+}
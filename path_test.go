@@ -0,0 +1,218 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const pathTestDoc = `{
+	"status": 200,
+	"data": {
+		"users": [
+			{"name": "John", "email": "john@example.com", "tags": ["a"]},
+			{"name": "Jean"},
+			{"name": "Jane", "email": "jane@example.com", "tags": ["b", "c"]}
+		],
+		"a.b": {"#": true},
+		"empty": []
+	}
+}`
+
+var PathTestsRaw = []struct {
+	Path string
+	Raw  string
+	JSONType
+	Error error
+}{
+	{`status`, `200`, Number, nil},
+	{`data.users.0.name`, `"John"`, String, nil},
+	{`data.users.2.tags`, `["b", "c"]`, Array, nil},
+	{`data.users.#`, `3`, Number, nil},
+	{`data.users.#.email`, `["john@example.com","jane@example.com"]`, Array,
+		nil},
+	{`data.users.#.tags.#`, `[1,2]`, Array, nil},
+	{`data.users.#.tags.0`, `["a","b"]`, Array, nil},
+	{`data.empty.#.x`, `[]`, Array, nil},
+	{`data.empty.#`, `0`, Number, nil},
+	{`data.a\.b.\#`, `true`, Boolean, nil},
+
+	{`data.users.3`, ``, InvalidJSON, ErrNotFound},
+	{`data.users.-`, ``, InvalidJSON, ErrNotFound},
+	{`data.users.x`, ``, InvalidJSON, ErrInvalidIndex},
+	{`data.users.99999999999999999999`, ``, InvalidJSON, ErrNotFound},
+	{`data.missing`, ``, InvalidJSON, ErrNotFound},
+	{`status.x`, ``, InvalidJSON, ErrUnexpectedType},
+	{`data\`, ``, InvalidJSON, ErrInvalidPath},
+}
+
+func TestGetPath(t *testing.T) {
+	for _, test := range PathTestsRaw {
+		raw, jType, err := GetPath([]byte(pathTestDoc), test.Path)
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Path, test.Error,
+				err)
+		}
+		if string(raw) != test.Raw || jType != test.JSONType {
+			t.Fatalf("[%s] Want: %s (%v); Have: %s (%v)", test.Path, test.Raw,
+				test.JSONType, raw, jType)
+		}
+	}
+
+	// The empty path selects the whole (valid) document.
+	raw, jType, err := GetPath([]byte(" [1] "), "")
+	if err != nil || string(raw) != "[1]" || jType != Array {
+		t.Fatalf("Unexpected result: %s (%v) (error: %v)", raw, jType, err)
+	}
+	for _, in := range []string{``, ` `, `[1] x`, `{"a":}`} {
+		if _, _, err = GetPath([]byte(in), ""); err == nil {
+			t.Fatalf("[%s] Want an error", in)
+		}
+		if _, _, err = GetPath([]byte(in), "a"); err == nil {
+			t.Fatalf("[%s] Want an error", in)
+		}
+	}
+}
+
+func TestGetPath_Errors(t *testing.T) {
+	_, _, err := GetPath([]byte(pathTestDoc), `data.users.1.email`)
+
+	var pathErr *PathError
+	if !errors.As(err, &pathErr) || pathErr.Index != 3 ||
+		pathErr.Component != "email" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `path "data.users.1.email": component "email": value not found`
+	if err.Error() != want {
+		t.Fatalf("Want: %s; Have: %s", want, err)
+	}
+
+	// Syntax errors are not skipped by '#'.
+	_, _, err = GetPath([]byte(`[{"a":1},{"a" 2}]`), `#.a`)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Offset != 14 {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGetPath_Payload(t *testing.T) {
+	raw, _, err := GetPath([]byte(pathTestDoc), "data.users.#.name")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p := AcquirePayload().WithArray()
+	defer ReleasePayload(p)
+	if err = p.UnmarshalJSON(raw); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if names := *p.GetArray().(*[]interface{}); len(names) != 3 ||
+		names[2] != "Jane" {
+		t.Fatalf("Unexpected value: %v", names)
+	}
+}
+
+func BenchmarkGetPath(b *testing.B) {
+	doc := []byte(pathTestDoc)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := GetPath(doc, "data.users.2.email"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	const doc = "{\n\t\"a\": 1.50,\n\t\"b\": [1, 2],\n\t\"c\": {}\n}"
+	tests := []struct {
+		Path   string
+		Value  interface{}
+		Create bool
+		Want   string
+		Error  error
+	}{
+		{`a`, "<x>", false,
+			"{\n\t\"a\": \"<x>\",\n\t\"b\": [1, 2],\n\t\"c\": {}\n}", nil},
+		{`b.1`, json.RawMessage(` {"x": 1} `), false,
+			"{\n\t\"a\": 1.50,\n\t\"b\": [1, {\"x\": 1}],\n\t\"c\": {}\n}", nil},
+		{`trace_id`, "t1", false,
+			"{\n\t\"a\": 1.50,\n\t\"b\": [1, 2],\n\t\"c\": {},\"trace_id\":\"t1\"\n}",
+			nil},
+		{`c.d\.e`, true, false,
+			"{\n\t\"a\": 1.50,\n\t\"b\": [1, 2],\n\t\"c\": {\"d.e\":true}\n}", nil},
+		{`b.-`, nil, false,
+			"{\n\t\"a\": 1.50,\n\t\"b\": [1, 2,null],\n\t\"c\": {}\n}", nil},
+		{`b.2`, 3, false,
+			"{\n\t\"a\": 1.50,\n\t\"b\": [1, 2,3],\n\t\"c\": {}\n}", nil},
+		{`c.x.-.y`, 1, true,
+			"{\n\t\"a\": 1.50,\n\t\"b\": [1, 2],\n\t\"c\": {\"x\":[{\"y\":1}]}\n}",
+			nil},
+		{``, []int{1}, false, `[1]`, nil},
+
+		{`c.x.y`, 1, false, ``, ErrNotFound},
+		{`b.3`, 1, false, ``, ErrNotFound},
+		{`c.x.1`, 1, true, ``, ErrNotFound},
+		{`b.#`, 1, false, ``, ErrInvalidIndex},
+		{`a.x`, 1, true, ``, ErrUnexpectedType},
+		{`a`, json.RawMessage(`{`), false, ``, ErrUnexpectedEnd},
+		{`a\`, 1, false, ``, ErrInvalidPath},
+	}
+
+	for _, test := range tests {
+		have, err := SetPath([]byte(doc), test.Path, test.Value, test.Create)
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Path, test.Error,
+				err)
+		}
+		if string(have) != test.Want {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.Path, test.Want, have)
+		}
+	}
+
+	// Empty containers don't get a comma.
+	have, err := SetPath([]byte(`[ ]`), "-", 1)
+	if err != nil || string(have) != `[1 ]` {
+		t.Fatalf("Unexpected result: %s (error: %v)", have, err)
+	}
+	if _, err = SetPath([]byte(` `), "a", 1); err == nil {
+		t.Fatalf("Want an error")
+	}
+}
+
+func TestDeletePath(t *testing.T) {
+	const doc = "{\n\t\"a\": 1,\n\t\"b\": [1, 2, 3],\n\t\"a\": 2\n}"
+	tests := []struct {
+		Doc   string
+		Path  string
+		Want  string
+		Error error
+	}{
+		{doc, `a`, "{\n\t\"b\": [1, 2, 3],\n\t\"a\": 2\n}", nil},
+		{doc, `b.0`, "{\n\t\"a\": 1,\n\t\"b\": [2, 3],\n\t\"a\": 2\n}", nil},
+		{doc, `b.1`, "{\n\t\"a\": 1,\n\t\"b\": [1, 3],\n\t\"a\": 2\n}", nil},
+		{doc, `b.2`, "{\n\t\"a\": 1,\n\t\"b\": [1, 2],\n\t\"a\": 2\n}", nil},
+		{`{"password": "x"}`, `password`, `{}`, nil},
+		{`{"a": {"b": [ 1 ]}}`, `a.b.0`, `{"a": {"b": [  ]}}`, nil},
+
+		{doc, `c`, ``, ErrNotFound},
+		{doc, `c.d`, ``, ErrNotFound},
+		{doc, `b.3`, ``, ErrNotFound},
+		{doc, `b.-`, ``, ErrNotFound},
+		{`[1, 2, 3]`, `-`, ``, ErrNotFound},
+		{doc, `b.x`, ``, ErrInvalidIndex},
+		{doc, `a.x`, ``, ErrUnexpectedType},
+		{doc, ``, ``, ErrInvalidPath},
+		{`{"a":1,}`, `b`, ``, ErrInvalidJSON},
+	}
+
+	for _, test := range tests {
+		have, err := DeletePath([]byte(test.Doc), test.Path)
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Path, test.Error,
+				err)
+		}
+		if string(have) != test.Want {
+			t.Fatalf("[%s] Want: %s; Have: %s", test.Path, test.Want, have)
+		}
+	}
+}
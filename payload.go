@@ -3,6 +3,7 @@ package jsonutils
 import (
 	"bytes"
 	"encoding/json"
+	"math/big"
 	"strconv"
 	"sync"
 )
@@ -29,7 +30,8 @@ func WithDefaultObject() interface{} { return new(map[string]interface{}) }
 // ReleasePayload when it's no longer needed.
 //
 // Payload by default returns an unmarshaling error for any value. To configure
-// it use the With* methods.
+// it use the With* methods. To reuse the same configuration, even across
+// goroutines, use a PayloadSpec.
 //
 // Payload has the following restrictions:
 //	- It cannot be compared to other Payload object. The comparison should be
@@ -40,8 +42,12 @@ func WithDefaultObject() interface{} { return new(map[string]interface{}) }
 //
 // This restrictions aim at improving code quality and performance.
 type Payload struct {
-	_        noCopy
-	with     [maxJSONType]bool
+	_ noCopy
+	payloadConfig
+
+	// PayloadSpec that created this Payload, if any
+	spec *PayloadSpec
+
 	jsonType JSONType
 
 	// Which of the elements in this struct is holding the value
@@ -51,18 +57,43 @@ type Payload struct {
 	pBool bool
 
 	// Number Payload
-	numType GoMapping // Hint for the unmarshaler on where to put Number values
-	pInt    int64
-	pUint   uint64
-	pFloat  float64
+	pInt   int64
+	pUint  uint64
+	pFloat float64
+
+	// Exact Number Payloads. The original text is kept in pNumber.
+	pNumber   string
+	pBigInt   *big.Int
+	pBigFloat *big.Float
+	pBigRat   *big.Rat
 
 	// String Payload
 	pString string
 
 	// Array and Object Payloads
+	pOther interface{}
+
+	// Raw Payload
+	pRaw []byte
+}
+
+// payloadConfig holds the configuration of a Payload, set with the With*
+// methods.
+type payloadConfig struct {
+	with [maxJSONType]bool
+
+	// Hint for the unmarshaler on where to put Number values
+	numType GoMapping
+
+	// Array and Object factories
 	arrayFactory  PayloadFactory
 	objectFactory PayloadFactory
-	pOther        interface{}
+
+	// Decodes Array and Object values instead of the factories, if set
+	discriminator *Discriminator
+
+	retainRaw bool // Keep a copy of the input for every JSON Data Type
+	lazy      bool // Don't decode Array and Object values
 }
 
 // AcquirePayload returns a new Payload from the internal pool.
@@ -85,26 +116,33 @@ func AcquirePayload() *Payload {
 // ReleasePayload releases the resources associated with the Payload. After
 // calling this function, the reference associated with this Payload should not
 // be used, otherwise unexpected errors could arise.
+//
+// If the Payload was acquired from a PayloadSpec then it's returned to that
+// PayloadSpec, keeping its configuration.
 func ReleasePayload(p *Payload) {
-	if p != nil {
+	switch {
+	case p == nil:
+	case p.spec != nil:
+		p.spec.release(p)
+	default:
 		p.Reset()
 		payloadPool.Put(p)
 	}
 }
 
-// Assert at compile-time that we implement the JSON Unmarshaler interface.
-var _ json.Unmarshaler = (*Payload)(nil)
+// Assert at compile-time that we implement the JSON Marshaler and Unmarshaler
+// interfaces.
+var (
+	_ json.Marshaler   = (*Payload)(nil)
+	_ json.Unmarshaler = (*Payload)(nil)
+)
 
 // Reset clears all configurations and values. After this operation the object
 // gets to an initial state.
 func (p *Payload) Reset() {
 	p.Clear()
-	for i := range p.with {
-		p.with[i] = false
-	}
-	p.arrayFactory = nil
-	p.objectFactory = nil
-	p.numType = GoInvalidMapping
+	p.payloadConfig = payloadConfig{}
+	p.spec = nil
 }
 
 // Clear removes all the associated data saved in the Payload but keeping all
@@ -116,11 +154,21 @@ func (p *Payload) Clear() {
 	p.pInt = 0
 	p.pUint = 0
 	p.pFloat = 0
+	p.pNumber = ""
+	p.pBigInt = nil
+	p.pBigFloat = nil
+	p.pBigRat = nil
 	p.pString = ""
 	p.pOther = nil
+	p.pRaw = nil
 }
 
 // UnmarshalJSON implements the JSON Unmarshaler interface.
+//
+// Besides the errors returned by TypeOf and encoding/json, it returns an
+// *UnexpectedTypeError if the Payload was not configured to accept the JSON
+// Data Type received, and a *NumberError if a JSON Number could not be held by
+// the Go type configured.
 func (p *Payload) UnmarshalJSON(b []byte) error {
 	p.Clear() // Reset state before attempting unmarshal.
 
@@ -129,37 +177,61 @@ func (p *Payload) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	start := skipSpace(b, 0)
 	if p.jsonType == InvalidJSON || !p.with[p.jsonType] {
-		return ErrUnexpectedType
+		return p.unexpectedType(p.jsonType, start)
 	}
 
-	switch p.jsonType {
-	case Array:
-		p.mapping = GoOther
-		p.pOther = p.arrayFactory()
-		err = json.Unmarshal(b, p.pOther)
+	lazy := p.lazy && (p.jsonType == Array || p.jsonType == Object)
+	if p.retainRaw || lazy {
+		p.pRaw = append([]byte(nil), b...)
+	}
+	b = trimSpace(b)
 
-	case Object:
+	switch p.jsonType {
+	case Array, Object:
+		if lazy {
+			p.mapping = GoRaw
+			break
+		}
 		p.mapping = GoOther
-		p.pOther = p.objectFactory()
-		err = json.Unmarshal(b, p.pOther)
+		switch {
+		case p.jsonType == Object && p.objectFactory != nil:
+			p.pOther = p.objectFactory()
+			err = json.Unmarshal(b, p.pOther)
+		case p.jsonType == Array && p.arrayFactory != nil:
+			p.pOther = p.arrayFactory()
+			err = json.Unmarshal(b, p.pOther)
+		case p.jsonType == Object:
+			p.pOther, err = p.discriminator.Decode(b)
+		default:
+			var v []interface{}
+			v, err = p.discriminator.DecodeArray(b)
+			p.pOther = &v
+		}
 
 	case Null:
 		p.mapping = GoNil
 
 	case String:
 		p.mapping = GoString
-		p.pString, err = strconv.Unquote(bytesToString(b))
+		p.pString, err = Unquote(b)
 
 	case Number:
 		p.mapping = p.numType
 		switch p.mapping {
+		case goAutoNumber:
+			err = p.setAutoNumber(b)
 		case GoInt:
 			p.pInt, err = strconv.ParseInt(bytesToString(b), 10, 64)
 		case GoFloat:
 			p.pFloat, err = strconv.ParseFloat(bytesToString(b), 64)
 		case GoUint:
 			p.pUint, err = strconv.ParseUint(bytesToString(b), 10, 64)
+		case GoJSONNumber:
+			p.pNumber = string(b)
+		case GoBigInt, GoBigFloat, GoBigRat:
+			err = p.setBigNumber(b)
 		}
 
 	case Boolean:
@@ -168,12 +240,111 @@ func (p *Payload) UnmarshalJSON(b []byte) error {
 	}
 
 	if err != nil {
+		if p.jsonType == Number {
+			err = newNumberError(string(b), p.mapping, start, err)
+		}
 		p.mapping = GoInvalidMapping
+		p.pRaw = nil
+	}
+
+	return err
+}
+
+// unexpectedType returns an *UnexpectedTypeError for a value of the JSON Data
+// Type got, listing the JSON Data Types allowed by the configuration.
+func (p *Payload) unexpectedType(got JSONType, offset int) error {
+	e := &UnexpectedTypeError{Got: got, Offset: int64(offset)}
+	for t := Object; t < maxJSONType; t++ {
+		if p.with[t] {
+			e.Allowed = append(e.Allowed, t)
+		}
+	}
+	return e
+}
+
+// MarshalJSON implements the JSON Marshaler interface.
+//
+// The value held is encoded according to its GoMapping, so a Payload loaded
+// through unmarshaling is encoded back into the same JSON value. If the Payload
+// holds no value (its GoMapping is GoInvalidMapping) then JSON Null is
+// returned. If the Payload retained the raw input (see WithRetainRaw and
+// WithLazyDecoding), then exactly that input is returned.
+func (p *Payload) MarshalJSON() ([]byte, error) {
+	if p.pRaw != nil {
+		return p.GetRaw(), nil
+	}
+
+	switch p.mapping {
+	case GoOther:
+		return json.Marshal(p.pOther)
+	case GoString:
+		return json.Marshal(p.pString)
+	case GoInt:
+		return strconv.AppendInt(nil, p.pInt, 10), nil
+	case GoUint:
+		return strconv.AppendUint(nil, p.pUint, 10), nil
+	case GoFloat:
+		return json.Marshal(p.pFloat)
+	case GoBool:
+		return strconv.AppendBool(nil, p.pBool), nil
+	case GoJSONNumber, GoBigInt, GoBigFloat, GoBigRat:
+		return []byte(p.pNumber), nil
+	}
+	return append([]byte(nil), bNull...), nil
+}
+
+// setAutoNumber stores a JSON Number choosing the narrowest mapping that can
+// hold it, as documented in WithAutoNumber.
+func (p *Payload) setAutoNumber(b []byte) error {
+	s := bytesToString(b)
+	switch kind, _, _ := scanNumber(b, 0); kind {
+	case Integer, NegativeInteger:
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			p.mapping, p.pInt = GoInt, v
+			return nil
+		}
+		if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			p.mapping, p.pUint = GoUint, v
+			return nil
+		}
 	}
 
+	var err error
+	p.mapping = GoFloat
+	p.pFloat, err = strconv.ParseFloat(s, 64)
 	return err
 }
 
+// setBigNumber stores a JSON Number as a math/big value, according to the
+// mapping already set, and keeps its original text.
+func (p *Payload) setBigNumber(b []byte) error {
+	p.pNumber = string(b)
+
+	var ok bool
+	switch p.mapping {
+	case GoBigInt:
+		if kind, _, _ := scanNumber(b, 0); kind == Integer ||
+			kind == NegativeInteger {
+			p.pBigInt, ok = new(big.Int).SetString(p.pNumber, 10)
+		}
+	case GoBigFloat:
+		// Use enough precision to hold all the decimal digits.
+		var err error
+		prec := uint(len(b))*4 + 64
+		p.pBigFloat, _, err = big.ParseFloat(p.pNumber, 10, prec,
+			big.ToNearestEven)
+		ok = err == nil
+	case GoBigRat:
+		p.pBigRat, ok = new(big.Rat).SetString(p.pNumber)
+	}
+
+	if !ok {
+		return &strconv.NumError{Func: "SetString", Num: p.pNumber,
+			Err: strconv.ErrSyntax}
+	}
+	return nil
+}
+
 // Get retrieves the Payload value as an interface{}.
 //
 // 	- If the Payload was never loaded (through JSON unmarshaling) nil and
@@ -202,10 +373,218 @@ func (p *Payload) Get() (interface{}, GoMapping) {
 		ret = p.pUint
 	case GoBool:
 		ret = p.pBool
+	case GoJSONNumber:
+		ret = json.Number(p.pNumber)
+	case GoBigInt:
+		ret = p.GetBigInt()
+	case GoBigFloat:
+		ret = p.GetBigFloat()
+	case GoBigRat:
+		ret = p.GetBigRat()
+	case GoRaw:
+		ret = p.GetRaw()
 	}
 	return ret, p.mapping
 }
 
+// set clears the Payload and prepares it to hold a value of the given JSON Data
+// Type and GoMapping. It fails if the Payload was not configured to accept that
+// JSON Data Type, unless force is set.
+func (p *Payload) set(t JSONType, m GoMapping, force []bool) error {
+	if !p.with[t] && (len(force) == 0 || !force[0]) {
+		return p.unexpectedType(t, 0)
+	}
+	p.Clear()
+	p.jsonType = t
+	p.mapping = m
+	return nil
+}
+
+// SetNull sets the Payload value to JSON Null.
+//
+// Like the other Set* methods, it returns an *UnexpectedTypeError, which
+// matches ErrUnexpectedType when using errors.Is, and leaves the Payload
+// unmodified if it was not configured to accept that JSON Data Type
+// (in this case, with WithNull). Passing force as true bypasses this check.
+func (p *Payload) SetNull(force ...bool) error {
+	return p.set(Null, GoNil, force)
+}
+
+// SetBool sets the Payload value to a JSON Boolean.
+func (p *Payload) SetBool(v bool, force ...bool) error {
+	err := p.set(Boolean, GoBool, force)
+	if err == nil {
+		p.pBool = v
+	}
+	return err
+}
+
+// SetString sets the Payload value to a JSON String.
+func (p *Payload) SetString(v string, force ...bool) error {
+	err := p.set(String, GoString, force)
+	if err == nil {
+		p.pString = v
+	}
+	return err
+}
+
+// SetInt sets the Payload value to a JSON Number held as an int64. Any of
+// WithNumber, WithInt, WithUint or WithFloat allow it.
+func (p *Payload) SetInt(v int64, force ...bool) error {
+	err := p.set(Number, GoInt, force)
+	if err == nil {
+		p.pInt = v
+	}
+	return err
+}
+
+// SetUint sets the Payload value to a JSON Number held as a uint64. Any of
+// WithNumber, WithInt, WithUint or WithFloat allow it.
+func (p *Payload) SetUint(v uint64, force ...bool) error {
+	err := p.set(Number, GoUint, force)
+	if err == nil {
+		p.pUint = v
+	}
+	return err
+}
+
+// SetFloat sets the Payload value to a JSON Number held as a float64. Any of
+// WithNumber, WithInt, WithUint or WithFloat allow it.
+func (p *Payload) SetFloat(v float64, force ...bool) error {
+	err := p.set(Number, GoFloat, force)
+	if err == nil {
+		p.pFloat = v
+	}
+	return err
+}
+
+// SetJSONNumber sets the Payload value to a JSON Number held as a
+// json.Number, whose text is used as is when marshaling. Any of the With*
+// methods for JSON Number allow it.
+//
+// Like SetBigInt, SetBigFloat and SetBigRat, it returns a *NumberError and
+// leaves the Payload unmodified if the value cannot be written as a JSON
+// Number.
+func (p *Payload) SetJSONNumber(v json.Number, force ...bool) error {
+	return p.setNumber(GoJSONNumber, string(v), force)
+}
+
+// SetBigInt sets the Payload value to a JSON Number held as a *big.Int. Any
+// of the With* methods for JSON Number allow it. Like SetBigFloat and
+// SetBigRat, it keeps a copy of v, so changing v afterwards doesn't change the
+// Payload.
+func (p *Payload) SetBigInt(v *big.Int, force ...bool) error {
+	var text string
+	if v != nil {
+		text = v.String()
+	}
+	err := p.setNumber(GoBigInt, text, force)
+	if err == nil {
+		p.pBigInt = new(big.Int).Set(v)
+	}
+	return err
+}
+
+// SetBigFloat sets the Payload value to a JSON Number held as a *big.Float,
+// which is written with the fewest decimal digits that represent it exactly.
+// Any of the With* methods for JSON Number allow it, but infinite values are
+// rejected.
+func (p *Payload) SetBigFloat(v *big.Float, force ...bool) error {
+	var text string
+	if v != nil {
+		text = v.Text('g', -1)
+	}
+	err := p.setNumber(GoBigFloat, text, force)
+	if err == nil {
+		p.pBigFloat = new(big.Float).Copy(v)
+	}
+	return err
+}
+
+// SetBigRat sets the Payload value to a JSON Number held as a *big.Rat. Any
+// of the With* methods for JSON Number allow it, but the value must have a
+// finite decimal representation, so values like 1/3 are rejected.
+func (p *Payload) SetBigRat(v *big.Rat, force ...bool) error {
+	err := p.setNumber(GoBigRat, ratText(v), force)
+	if err == nil {
+		p.pBigRat = new(big.Rat).Set(v)
+	}
+	return err
+}
+
+// setNumber sets the Payload value to the JSON Number text, held with the
+// mapping m, which must be one of the mappings that keep the text.
+func (p *Payload) setNumber(m GoMapping, text string, force []bool) error {
+	b := []byte(text)
+	if len(b) == 0 {
+		return newNumberError(text, m, 0, strconv.ErrSyntax)
+	}
+	if _, end, err := scanNumber(b, 0); err != nil || end != len(b) {
+		return newNumberError(text, m, 0, strconv.ErrSyntax)
+	}
+
+	err := p.set(Number, m, force)
+	if err == nil {
+		p.pNumber = text
+	}
+	return err
+}
+
+// ratText returns the exact decimal representation of v, or an empty string
+// if v is nil or it has no finite decimal representation.
+func ratText(v *big.Rat) string {
+	if v == nil {
+		return ""
+	}
+	if v.IsInt() {
+		return v.Num().String()
+	}
+
+	// A denominator of the form 2^a * 5^b needs max(a, b) decimal digits.
+	d, r := new(big.Int).Set(v.Denom()), new(big.Int)
+	digits := [2]int{}
+	for i, f := range []int64{2, 5} {
+		factor := big.NewInt(f)
+		for {
+			q, _ := new(big.Int).QuoRem(d, factor, r)
+			if r.Sign() != 0 {
+				break
+			}
+			d = q
+			digits[i]++
+		}
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return ""
+	}
+	if digits[0] < digits[1] {
+		digits[0] = digits[1]
+	}
+	return v.FloatString(digits[0])
+}
+
+// SetObject sets the Payload value to a JSON Object. The value should be of
+// the same kind as the ones returned by the factory passed to WithObject, so it
+// can be retrieved in the same way with GetObject.
+func (p *Payload) SetObject(v interface{}, force ...bool) error {
+	err := p.set(Object, GoOther, force)
+	if err == nil {
+		p.pOther = v
+	}
+	return err
+}
+
+// SetArray sets the Payload value to a JSON Array. The value should be of the
+// same kind as the ones returned by the factory passed to WithArray, so it can
+// be retrieved in the same way with GetArray.
+func (p *Payload) SetArray(v interface{}, force ...bool) error {
+	err := p.set(Array, GoOther, force)
+	if err == nil {
+		p.pOther = v
+	}
+	return err
+}
+
 // GetObject is an alias for GetOther for conveniency.
 func (p *Payload) GetObject() interface{} { return p.GetOther() }
 
@@ -265,6 +644,64 @@ func (p *Payload) GetFloat() float64 {
 	return p.pFloat
 }
 
+// GetJSONNumber retrieves the Payload value as a json.Number, which holds the
+// exact text of the JSON Number.
+//
+// It panics if the JSON Data Type was not a Number or if the Number mapping was
+// not meant for a json.Number.
+func (p *Payload) GetJSONNumber() json.Number {
+	p.mapping.panicIfNot(GoJSONNumber)
+	return json.Number(p.pNumber)
+}
+
+// GetBigInt retrieves the Payload value as a *big.Int. Like GetBigFloat and
+// GetBigRat, it returns a copy, so changing it doesn't change the Payload.
+//
+// It panics if the JSON Data Type was not a Number or if the Number mapping was
+// not meant for a *big.Int.
+func (p *Payload) GetBigInt() *big.Int {
+	p.mapping.panicIfNot(GoBigInt)
+	return new(big.Int).Set(p.pBigInt)
+}
+
+// GetBigFloat retrieves the Payload value as a *big.Float.
+//
+// It panics if the JSON Data Type was not a Number or if the Number mapping was
+// not meant for a *big.Float.
+func (p *Payload) GetBigFloat() *big.Float {
+	p.mapping.panicIfNot(GoBigFloat)
+	return new(big.Float).Copy(p.pBigFloat)
+}
+
+// GetBigRat retrieves the Payload value as a *big.Rat.
+//
+// It panics if the JSON Data Type was not a Number or if the Number mapping was
+// not meant for a *big.Rat.
+func (p *Payload) GetBigRat() *big.Rat {
+	p.mapping.panicIfNot(GoBigRat)
+	return new(big.Rat).Set(p.pBigRat)
+}
+
+// GetRaw returns a copy of the input that was last unmarshaled into the
+// Payload, as long as WithRetainRaw was used, or WithLazyDecoding was used and
+// the JSON value was an Array or an Object. Otherwise, it returns nil. It never
+// panics.
+func (p *Payload) GetRaw() json.RawMessage {
+	return append(json.RawMessage(nil), p.pRaw...)
+}
+
+// DecodeInto decodes the raw input retained by the Payload into v, using
+// json.Unmarshal. This is the way to retrieve Array and Object values when
+// WithLazyDecoding is used, but it works with any retained input (see GetRaw).
+//
+// It returns ErrNoRawValue if the Payload didn't retain its input.
+func (p *Payload) DecodeInto(v interface{}) error {
+	if p.pRaw == nil {
+		return ErrNoRawValue
+	}
+	return json.Unmarshal(p.pRaw, v)
+}
+
 // IsNil reports whether the Payload value was a JSON Null. It never panics.
 //
 // Note that if you are using a pointer to Payload the JSON Unmarshaler can
@@ -347,6 +784,19 @@ func (p *Payload) WithFloat(enable ...bool) *Payload {
 	return p.withNum(GoFloat, enable...)
 }
 
+// WithAutoNumber configures the Payload to accept a JSON Number value
+// (disabled by default) and to choose how to interpret each value: integers are
+// held as an int64 if they fit, otherwise as a uint64 if they fit, and all the
+// other numbers (including integers that don't fit and numbers with a fraction
+// or an exponent) are held as a float64. The mapping chosen is reported by
+// GetMapping.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithAutoNumber(enable ...bool) *Payload {
+	return p.withNum(goAutoNumber, enable...)
+}
+
 // WithInt configures the Payload to accept a JSON Number value (disabled by
 // default) and interpret it as a int64.
 //
@@ -365,6 +815,76 @@ func (p *Payload) WithUint(enable ...bool) *Payload {
 	return p.withNum(GoUint, enable...)
 }
 
+// WithJSONNumber configures the Payload to accept a JSON Number value
+// (disabled by default) and interpret it as a json.Number, which holds its
+// exact text.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithJSONNumber(enable ...bool) *Payload {
+	return p.withNum(GoJSONNumber, enable...)
+}
+
+// WithBigInt configures the Payload to accept a JSON Number value (disabled by
+// default) and interpret it as a *big.Int. Numbers with a fraction or an
+// exponent are rejected.
+//
+// Like with WithJSONNumber, WithBigFloat and WithBigRat, the exact text of the
+// JSON Number is kept and it's used when marshaling the Payload back to JSON.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithBigInt(enable ...bool) *Payload {
+	return p.withNum(GoBigInt, enable...)
+}
+
+// WithBigFloat configures the Payload to accept a JSON Number value (disabled
+// by default) and interpret it as a *big.Float, with enough precision to hold
+// all of its decimal digits.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithBigFloat(enable ...bool) *Payload {
+	return p.withNum(GoBigFloat, enable...)
+}
+
+// WithBigRat configures the Payload to accept a JSON Number value (disabled by
+// default) and interpret it as a *big.Rat, which holds its exact value.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithBigRat(enable ...bool) *Payload {
+	return p.withNum(GoBigRat, enable...)
+}
+
+// WithRetainRaw configures the Payload to keep a copy of the input for every
+// JSON Data Type (disabled by default), which can be retrieved with GetRaw even
+// after the value was decoded. This is useful to forward or hash the exact
+// bytes received, and it's also what MarshalJSON returns.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithRetainRaw(enable ...bool) *Payload {
+	p.retainRaw = len(enable) == 0 || enable[0]
+	return p
+}
+
+// WithLazyDecoding configures the Payload to leave JSON Array and Object
+// values undecoded (disabled by default), so the factories set with WithArray
+// and WithObject are not called. Instead, a copy of the input is kept and the
+// GoMapping is set to GoRaw. The value can then be decoded on demand with
+// DecodeInto, or retrieved with GetRaw.
+//
+// Note that Array and Object values still need to be enabled with WithArray
+// and WithObject.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *Payload) WithLazyDecoding(enable ...bool) *Payload {
+	p.lazy = len(enable) == 0 || enable[0]
+	return p
+}
+
 // WithArray configures the Payload to accept a JSON Array value (disabled by
 // default).
 //
@@ -394,3 +914,23 @@ func (p *Payload) WithObject(f ...PayloadFactory) *Payload {
 	p.objectFactory = f[0]
 	return p
 }
+
+// WithDiscriminator configures the Payload to accept JSON Object and Array
+// values (disabled by default), decoding them with d:
+//	- A JSON Object is decoded with d.Decode, and GetObject returns the value
+//		created by the matching PayloadFactory.
+//	- A JSON Array is decoded with d.DecodeArray, and GetArray returns a
+//		*[]interface{} holding the decoded elements.
+//
+// Passing nil disables this configuration as well as accepting JSON Object
+// and Array values. Calling WithObject or WithArray afterwards overrides this
+// configuration for that JSON Data Type only, so calling WithArray(nil)
+// afterwards accepts only JSON Object values decoded with d.
+func (p *Payload) WithDiscriminator(d *Discriminator) *Payload {
+	p.with[Object] = d != nil
+	p.with[Array] = d != nil
+	p.discriminator = d
+	p.objectFactory = nil
+	p.arrayFactory = nil
+	return p
+}
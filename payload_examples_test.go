@@ -2,6 +2,7 @@ package jsonutils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -96,7 +97,7 @@ func ExamplePayload() {
 
 	// Unexpected data type: Number
 	err := json.Unmarshal(payloads["unexpected"], &GetUser)
-	if err != ErrUnexpectedType {
+	if !errors.Is(err, ErrUnexpectedType) {
 		fmt.Printf("unexpected error decoding JSON (want: ErrUnexpectedType)"+
 			": %v", err)
 		return
@@ -110,3 +111,33 @@ func ExamplePayload() {
 	// Output:
 	//
 }
+
+func ExamplePayloadOf() {
+	type User struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	}
+
+	// Accept a single User, an array of them or a message.
+	p := NewPayloadOf[User, []User]().WithObject().WithArray().WithString()
+
+	err := json.Unmarshal([]byte(`[{"id":1,"username":"johndoe"}]`), p)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	switch p.GetJSONType() {
+	case Object:
+		fmt.Println("one user:", p.GetObject().Username)
+	case Array:
+		// No type assertions needed, users is a []User.
+		users := p.GetArray()
+		fmt.Println("many users:", len(users), users[0].Username)
+	case String:
+		fmt.Println("message:", p.GetString())
+	}
+
+	// Output:
+	// many users: 1 johndoe
+}
@@ -2,7 +2,10 @@ package jsonutils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -65,7 +68,7 @@ var TestsPayloadRaw = []PayloadTest{
 		Name:          "Number using WithUint, failing since float received",
 		JSONData:      []byte(`34.1`),
 		Payload:       AcquirePayload().WithUint(),
-		Error:         "34.1",
+		Error:         "cannot decode number 34.1 as uint: invalid syntax",
 		MarshaledBack: "null",
 		JSONType:      Number,
 		GoMapping:     GoInvalidMapping,
@@ -81,6 +84,26 @@ var TestsPayloadRaw = []PayloadTest{
 		GoMapping:     GoString,
 	}, //*/
 
+	{
+		Name:          "String payload with JSON escapes",
+		JSONData:      []byte(`"\/\u00f1\ud83d\ude00"`),
+		Payload:       AcquirePayload().WithString(),
+		Error:         "",
+		MarshaledBack: `"/ñ😀"`,
+		JSONType:      String,
+		GoMapping:     GoString,
+	}, //*/
+
+	{
+		Name:          "String payload with Go-only escapes",
+		JSONData:      []byte(`"\x41"`),
+		Payload:       AcquirePayload().WithString(),
+		Error:         ErrInvalidString.Error(),
+		MarshaledBack: `null`,
+		JSONType:      String,
+		GoMapping:     GoInvalidMapping,
+	}, //*/
+
 	{
 		Name:          "Array payload",
 		JSONData:      []byte(`[1,2,3,"Lorem ipsum"]`),
@@ -101,6 +124,26 @@ var TestsPayloadRaw = []PayloadTest{
 		GoMapping:     GoOther,
 	}, //*/
 
+	{
+		Name:          "String payload with surrounding whitespace",
+		JSONData:      []byte(" \"Lorem ipsum\"\n"),
+		Payload:       AcquirePayload().WithString(),
+		Error:         "",
+		MarshaledBack: `"Lorem ipsum"`,
+		JSONType:      String,
+		GoMapping:     GoString,
+	}, //*/
+
+	{
+		Name:          "Number payload with surrounding whitespace",
+		JSONData:      []byte("\t-20 "),
+		Payload:       AcquirePayload().WithInt(),
+		Error:         "",
+		MarshaledBack: `-20`,
+		JSONType:      Number,
+		GoMapping:     GoInt,
+	}, //*/
+
 	{
 		Name:          "Decoding error propagation",
 		JSONData:      []byte(`whatever`),
@@ -115,7 +158,7 @@ var TestsPayloadRaw = []PayloadTest{
 		Name:          "Unexpected type",
 		JSONData:      []byte(`true`),
 		Payload:       AcquirePayload(),
-		Error:         ErrUnexpectedType.Error(),
+		Error:         "expected one of [], got boolean",
 		MarshaledBack: `null`,
 		JSONType:      Boolean,
 		GoMapping:     GoInvalidMapping,
@@ -145,7 +188,7 @@ var TestsPayloadRaw = []PayloadTest{
 		Name:          "WithUint then remove using WithNumber",
 		JSONData:      []byte(`-2`),
 		Payload:       AcquirePayload().WithUint().WithNumber(false),
-		Error:         ErrUnexpectedType.Error(),
+		Error:         "expected one of [], got number",
 		MarshaledBack: `null`,
 		JSONType:      Number,
 		GoMapping:     GoInvalidMapping,
@@ -155,7 +198,7 @@ var TestsPayloadRaw = []PayloadTest{
 		Name:          "WithFloat then deactivate it",
 		JSONData:      []byte(`3.14`),
 		Payload:       AcquirePayload().WithFloat(true).WithFloat(false),
-		Error:         ErrUnexpectedType.Error(),
+		Error:         "expected one of [], got number",
 		MarshaledBack: `null`,
 		JSONType:      Number,
 		GoMapping:     GoInvalidMapping,
@@ -171,6 +214,146 @@ var TestsPayloadRaw = []PayloadTest{
 		GoMapping:     GoOther,
 	}, //*/
 
+	{
+		Name:          "WithAutoNumber, int",
+		JSONData:      []byte(`42`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `42`,
+		JSONType:      Number,
+		GoMapping:     GoInt,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, negative int",
+		JSONData:      []byte(`-9223372036854775808`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `-9223372036854775808`,
+		JSONType:      Number,
+		GoMapping:     GoInt,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, uint",
+		JSONData:      []byte(`18446744073709551615`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `18446744073709551615`,
+		JSONType:      Number,
+		GoMapping:     GoUint,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, overflowing uint",
+		JSONData:      []byte(`18446744073709551616`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `18446744073709552000`,
+		JSONType:      Number,
+		GoMapping:     GoFloat,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, overflowing negative int",
+		JSONData:      []byte(`-9223372036854775809`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `-9223372036854776000`,
+		JSONType:      Number,
+		GoMapping:     GoFloat,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, fraction",
+		JSONData:      []byte(`42.5`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `42.5`,
+		JSONType:      Number,
+		GoMapping:     GoFloat,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, exponent",
+		JSONData:      []byte(`1e2`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "",
+		MarshaledBack: `100`,
+		JSONType:      Number,
+		GoMapping:     GoFloat,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber, out of range",
+		JSONData:      []byte(`1e400`),
+		Payload:       AcquirePayload().WithAutoNumber(),
+		Error:         "value out of range",
+		MarshaledBack: `null`,
+		JSONType:      Number,
+		GoMapping:     GoInvalidMapping,
+	}, //*/
+
+	{
+		Name:          "WithAutoNumber then deactivate it",
+		JSONData:      []byte(`42`),
+		Payload:       AcquirePayload().WithAutoNumber().WithAutoNumber(false),
+		Error:         "expected one of [], got number",
+		MarshaledBack: `null`,
+		JSONType:      Number,
+		GoMapping:     GoInvalidMapping,
+	}, //*/
+
+	{
+		Name:          "WithJSONNumber keeps the exact text",
+		JSONData:      []byte(`-123456789012345678901234567890.123456789012345678901e-5`),
+		Payload:       AcquirePayload().WithJSONNumber(),
+		Error:         "",
+		MarshaledBack: `-123456789012345678901234567890.123456789012345678901e-5`,
+		JSONType:      Number,
+		GoMapping:     GoJSONNumber,
+	}, //*/
+
+	{
+		Name:          "WithBigInt above 2^64",
+		JSONData:      []byte(`123456789012345678901234567890`),
+		Payload:       AcquirePayload().WithBigInt(),
+		Error:         "",
+		MarshaledBack: `123456789012345678901234567890`,
+		JSONType:      Number,
+		GoMapping:     GoBigInt,
+	}, //*/
+
+	{
+		Name:          "WithBigInt, failing since fraction received",
+		JSONData:      []byte(`1.5`),
+		Payload:       AcquirePayload().WithBigInt(),
+		Error:         "1.5",
+		MarshaledBack: `null`,
+		JSONType:      Number,
+		GoMapping:     GoInvalidMapping,
+	}, //*/
+
+	{
+		Name:          "WithLazyDecoding, object",
+		JSONData:      []byte(`{"name":"John"}`),
+		Payload:       AcquirePayload().WithObject().WithLazyDecoding(),
+		Error:         "",
+		MarshaledBack: `{"name":"John"}`,
+		JSONType:      Object,
+		GoMapping:     GoRaw,
+	}, //*/
+
+	{
+		Name:          "WithLazyDecoding, array not enabled",
+		JSONData:      []byte(`[1]`),
+		Payload:       AcquirePayload().WithObject().WithLazyDecoding(),
+		Error:         "expected one of [object], got array",
+		MarshaledBack: `null`,
+		JSONType:      Array,
+		GoMapping:     GoInvalidMapping,
+	}, //*/
+
 	/* Template
 	{
 		Name:          "",
@@ -209,8 +392,8 @@ func testPayloadHelper(test PayloadTest, reset bool, parallel bool,
 
 		// Assert JSON Type.
 		if test.JSONType != test.Payload.GetJSONType() {
-			t.Fatalf("[%s] Unexpected JSON Data Type\nWant Type: %d"+
-				"\nHave Type: %d", test.Name, test.JSONType,
+			t.Fatalf("[%s] Unexpected JSON Data Type\nWant Type: %v"+
+				"\nHave Type: %v", test.Name, test.JSONType,
 				test.Payload.jsonType)
 		}
 
@@ -227,6 +410,18 @@ func testPayloadHelper(test PayloadTest, reset bool, parallel bool,
 				test.MarshaledBack, b)
 		}
 
+		// Marshal the Payload itself and assert it round-trips.
+		b, err = json.Marshal(test.Payload)
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error marshaling Payload: %v",
+				test.Name, err)
+		}
+		if string(b) != test.MarshaledBack {
+			t.Fatalf("[%s] Failed marshaling Payload\nWant MarshalBack:"+
+				" %s\nHave MarshalBack: %s", test.Name,
+				test.MarshaledBack, b)
+		}
+
 		// Assert the correctness of the reported GoMapping.
 		wantMap := test.GoMapping
 		haveMap := test.Payload.GetMapping()
@@ -334,6 +529,344 @@ func TestPayload_Getters(t *testing.T) {
 
 }
 
+func TestPayload_MarshalJSON(t *testing.T) {
+	type Response struct {
+		Status int      `json:"status"`
+		Data   *Payload `json:"data"`
+	}
+
+	for _, test := range TestsPayloadRaw {
+		if test.Error != "" {
+			continue
+		}
+		t.Run(test.Name, func(t *testing.T) {
+			in := []byte(`{"status":200,"data":` + string(test.JSONData) + `}`)
+			resp := Response{Data: AcquirePayload()}
+			defer ReleasePayload(resp.Data)
+			resp.Data.with = test.Payload.with
+			resp.Data.numType = test.Payload.numType
+			resp.Data.arrayFactory = test.Payload.arrayFactory
+			resp.Data.objectFactory = test.Payload.objectFactory
+
+			if err := json.Unmarshal(in, &resp); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %v", err)
+			}
+			out, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %v", err)
+			}
+			want := `{"status":200,"data":` + test.MarshaledBack + `}`
+			if string(out) != want {
+				t.Fatalf("Failed round-trip\nWant: %s\nHave: %s", want, out)
+			}
+		})
+	}
+
+	p := AcquirePayload()
+	defer ReleasePayload(p)
+	for _, v := range []bool{true, false} {
+		p.pBool, p.mapping = v, GoBool
+		b, _ := p.MarshalJSON()
+		if want := strconv.FormatBool(v); string(b) != want {
+			t.Fatalf("Want: %s; Have: %s", want, b)
+		}
+	}
+}
+
+func TestPayload_Errors(t *testing.T) {
+	p := AcquirePayload().WithString().WithObject().WithInt()
+	defer ReleasePayload(p)
+
+	err := p.UnmarshalJSON([]byte(`  true`))
+	if err == nil || err.Error() != "expected one of [object string number], got boolean" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var typeErr *UnexpectedTypeError
+	if !errors.As(err, &typeErr) || !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Expected an *UnexpectedTypeError. Got: %#v", err)
+	}
+	if typeErr.Got != Boolean || typeErr.Offset != 2 ||
+		fmt.Sprint(typeErr.Allowed) != fmt.Sprint([]JSONType{Object, String, Number}) {
+		t.Fatalf("Unexpected error details: %#v", typeErr)
+	}
+
+	err = p.UnmarshalJSON([]byte(` 9223372036854775808`))
+	var numErr *NumberError
+	if !errors.As(err, &numErr) || !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("Expected a *NumberError wrapping strconv.ErrRange. Got: %#v",
+			err)
+	}
+	if numErr.Num != "9223372036854775808" || numErr.Mapping != GoInt ||
+		numErr.Offset != 1 {
+		t.Fatalf("Unexpected error details: %#v", numErr)
+	}
+
+	err = p.SetBool(true)
+	if !errors.As(err, &typeErr) || typeErr.Got != Boolean {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+}
+
+func TestPayload_Setters(t *testing.T) {
+	p := AcquirePayload()
+	defer ReleasePayload(p)
+
+	// Setters respect the configuration unless forced.
+	if err := p.SetString("Lorem ipsum"); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+	if p.GetMapping() != GoInvalidMapping || p.GetJSONType() != InvalidJSON {
+		t.Fatalf("Payload modified after failed set: %#v", p)
+	}
+	if err := p.SetString("Lorem ipsum", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := p.GetString(); val != "Lorem ipsum" {
+		t.Fatalf("Want: %v; Have: %v", "Lorem ipsum", val)
+	}
+
+	p.WithNull().WithBoolean().WithFloat().WithObject().WithArray()
+	obj := &map[string]interface{}{"name": "John"}
+	arr := &[]interface{}{"a", 1.5}
+	tests := []struct {
+		Name string
+		Set  func() error
+		JSONType
+		GoMapping
+		MarshaledBack string
+	}{
+		{"Null", func() error { return p.SetNull() }, Null, GoNil, `null`},
+		{"Bool", func() error { return p.SetBool(true) }, Boolean, GoBool,
+			`true`},
+		{"Int", func() error { return p.SetInt(-20) }, Number, GoInt, `-20`},
+		{"Uint", func() error { return p.SetUint(6543) }, Number, GoUint,
+			`6543`},
+		{"Float", func() error { return p.SetFloat(3.14) }, Number, GoFloat,
+			`3.14`},
+		{"JSONNumber", func() error { return p.SetJSONNumber("1.50e3") },
+			Number, GoJSONNumber, `1.50e3`},
+		{"BigInt", func() error { return p.SetBigInt(big.NewInt(-7)) },
+			Number, GoBigInt, `-7`},
+		{"BigFloat", func() error { return p.SetBigFloat(big.NewFloat(1e100)) },
+			Number, GoBigFloat, `1e+100`},
+		{"BigRat", func() error { return p.SetBigRat(big.NewRat(-3, 40)) },
+			Number, GoBigRat, `-0.075`},
+		{"Object", func() error { return p.SetObject(obj) }, Object, GoOther,
+			`{"name":"John"}`},
+		{"Array", func() error { return p.SetArray(arr) }, Array, GoOther,
+			`["a",1.5]`},
+	}
+	for _, test := range tests {
+		if err := test.Set(); err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.Name, err)
+		}
+		if test.JSONType != p.GetJSONType() || test.GoMapping != p.GetMapping() {
+			t.Fatalf("[%s] Want: %v, %v; Have: %v, %v", test.Name,
+				test.JSONType, test.GoMapping, p.GetJSONType(), p.GetMapping())
+		}
+		b, err := json.Marshal(p)
+		if err != nil || string(b) != test.MarshaledBack {
+			t.Fatalf("[%s] Want: %s; Have: %s (error: %v)", test.Name,
+				test.MarshaledBack, b, err)
+		}
+	}
+
+	if p.GetArray() != arr {
+		t.Fatalf("Want: %p; Have: %p", arr, p.GetArray())
+	}
+
+	// Values that cannot be written as a JSON Number are rejected.
+	for name, set := range map[string]func() error{
+		"JSONNumber": func() error { return p.SetJSONNumber("1.") },
+		"BigInt":     func() error { return p.SetBigInt(nil) },
+		"BigFloat": func() error {
+			return p.SetBigFloat(new(big.Float).SetInf(false))
+		},
+		"BigRat": func() error { return p.SetBigRat(big.NewRat(1, 3)) },
+	} {
+		var numErr *NumberError
+		if err := set(); !errors.As(err, &numErr) {
+			t.Fatalf("[%s] Unexpected error: %v", name, err)
+		}
+		if p.GetMapping() != GoOther {
+			t.Fatalf("[%s] The Payload was modified", name)
+		}
+	}
+	p.Clear()
+	if _, m := p.Get(); m != GoInvalidMapping {
+		t.Fatalf("Want: %v; Have: %v", GoInvalidMapping, m)
+	}
+	p.Reset()
+	if err := p.SetBool(false); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+}
+
+func TestPayload_BigNumbers(t *testing.T) {
+	p := AcquirePayload()
+	defer ReleasePayload(p)
+
+	text := "12345678901234567890.123456789012345678901"
+	want, _ := new(big.Rat).SetString(text)
+
+	p.WithBigRat()
+	if err := p.UnmarshalJSON([]byte(text)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := p.GetBigRat(); val.Cmp(want) != 0 {
+		t.Fatalf("Want: %v; Have: %v", want, val)
+	}
+	if v, m := p.Get(); m != GoBigRat || v.(*big.Rat).Cmp(want) != 0 {
+		t.Fatalf("Unexpected Get result: %v, %v", v, m)
+	}
+	if b, err := json.Marshal(p); err != nil || string(b) != text {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", text, b, err)
+	}
+
+	p.WithBigFloat()
+	if err := p.UnmarshalJSON([]byte(text)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := p.GetBigFloat().Text('f', 21); val != text {
+		t.Fatalf("Want: %v; Have: %v", text, val)
+	}
+	if b, err := json.Marshal(p); err != nil || string(b) != text {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", text, b, err)
+	}
+
+	p.WithBigInt()
+	if err := p.UnmarshalJSON([]byte(`-18446744073709551616`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := p.GetBigInt().String(); val != "-18446744073709551616" {
+		t.Fatalf("Want: %v; Have: %v", "-18446744073709551616", val)
+	}
+
+	p.WithJSONNumber()
+	if err := p.UnmarshalJSON([]byte(`1E+2`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := p.GetJSONNumber(); val != "1E+2" {
+		t.Fatalf("Want: %v; Have: %v", "1E+2", val)
+	}
+}
+
+func TestPayload_BigNumbersCopy(t *testing.T) {
+	p := AcquirePayload().WithBigInt()
+	defer ReleasePayload(p)
+
+	// Changing the value returned by a getter doesn't change the Payload.
+	if err := p.UnmarshalJSON([]byte(`100`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	v := p.GetBigInt()
+	v.Add(v, big.NewInt(1))
+	if val := p.GetBigInt().Int64(); val != 100 {
+		t.Fatalf("Want: %v; Have: %v", 100, val)
+	}
+	if b, err := json.Marshal(p); err != nil || string(b) != `100` {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", `100`, b, err)
+	}
+
+	// Neither does changing the value passed to a setter.
+	i, f, r := big.NewInt(5), big.NewFloat(0.5), big.NewRat(1, 2)
+	tests := []struct {
+		Name   string
+		Set    func() error
+		Change func()
+		Get    func() string
+		Want   string
+	}{
+		{"BigInt", func() error { return p.SetBigInt(i) },
+			func() { i.SetInt64(7) },
+			func() string { return p.GetBigInt().String() }, "5"},
+		{"BigFloat", func() error { return p.SetBigFloat(f) },
+			func() { f.SetInt64(7) },
+			func() string { return p.GetBigFloat().Text('g', -1) }, "0.5"},
+		{"BigRat", func() error { return p.SetBigRat(r) },
+			func() { r.SetInt64(7) },
+			func() string { return p.GetBigRat().String() }, "1/2"},
+	}
+	for _, test := range tests {
+		if err := test.Set(); err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.Name, err)
+		}
+		before, _ := json.Marshal(p)
+		test.Change()
+		if val := test.Get(); val != test.Want {
+			t.Fatalf("[%s] Want: %v; Have: %v", test.Name, test.Want, val)
+		}
+		if b, err := json.Marshal(p); err != nil ||
+			string(b) != string(before) {
+			t.Fatalf("[%s] Want: %s; Have: %s (error: %v)", test.Name,
+				before, b, err)
+		}
+	}
+}
+
+func TestPayload_RetainRaw(t *testing.T) {
+	p := AcquirePayload().WithRetainRaw().WithString().WithObject()
+	defer ReleasePayload(p)
+
+	// The exact bytes are kept and marshaled back, even after decoding.
+	in := []byte(`"\u00f1"`)
+	if err := p.UnmarshalJSON(in); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.GetString() != "ñ" || string(p.GetRaw()) != string(in) {
+		t.Fatalf("Unexpected values: %q, %s", p.GetString(), p.GetRaw())
+	}
+	in[0] = 'x' // The input can be reused by the caller.
+	// As well as the copy returned by GetRaw.
+	p.GetRaw()[0] = 'x'
+	if b, err := p.MarshalJSON(); err != nil || string(b) != `"\u00f1"` {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", `"\u00f1"`, b, err)
+	}
+	var s string
+	if err := p.DecodeInto(&s); err != nil || s != "ñ" {
+		t.Fatalf("Want: %s; Have: %s (error: %v)", "ñ", s, err)
+	}
+
+	// Errors and setters drop the raw input.
+	if err := p.UnmarshalJSON([]byte(`1`)); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+	if p.GetRaw() != nil {
+		t.Fatalf("Unexpected raw input: %s", p.GetRaw())
+	}
+	if err := p.UnmarshalJSON([]byte(`"a"`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := p.SetString("b"); err != nil || p.GetRaw() != nil {
+		t.Fatalf("Unexpected raw input: %s (error: %v)", p.GetRaw(), err)
+	}
+	if err := p.DecodeInto(&s); err != ErrNoRawValue {
+		t.Fatalf("Want: %v; Have: %v", ErrNoRawValue, err)
+	}
+
+	// Lazy decoding doesn't call the factories.
+	called := false
+	p.WithRetainRaw(false).WithLazyDecoding().WithObject(func() interface{} {
+		called = true
+		return new(Tag)
+	})
+	if err := p.UnmarshalJSON([]byte(`{"key":"K"}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if called || p.GetMapping() != GoRaw {
+		t.Fatalf("Unexpected decoding: %v, %v", called, p.GetMapping())
+	}
+	var tag Tag
+	if err := p.DecodeInto(&tag); err != nil || tag.Key != "K" {
+		t.Fatalf("Unexpected value: %v (error: %v)", tag, err)
+	}
+
+	// Strings are not retained without WithRetainRaw.
+	if err := p.UnmarshalJSON([]byte(`"a"`)); err != nil || p.GetRaw() != nil {
+		t.Fatalf("Unexpected raw input: %s (error: %v)", p.GetRaw(), err)
+	}
+}
+
 func TestAcquirePayload(t *testing.T) {
 	p := AcquirePayload()
 	ReleasePayload(p)
@@ -0,0 +1,316 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"math/big"
+)
+
+// PayloadOf is a type-safe counterpart of Payload. Instead of configuring
+// PayloadFactory functions that return interface{} values, the Go types used
+// to hold JSON Object and Array values are given as the type parameters O and
+// A, and the getters and setters use those types.
+//
+// It works exactly like Payload, with which it shares the same TypeOf-based
+// dispatch and With* configuration rules. As such, it has the same
+// restrictions. Its zero value is ready to be configured and used.
+type PayloadOf[O, A any] struct {
+	p Payload
+}
+
+// Assert at compile-time that we implement the JSON Marshaler and Unmarshaler
+// interfaces.
+var (
+	_ json.Marshaler   = (*PayloadOf[struct{}, []struct{}])(nil)
+	_ json.Unmarshaler = (*PayloadOf[struct{}, []struct{}])(nil)
+)
+
+// factoryOf returns a PayloadFactory for the type T.
+func factoryOf[T any]() interface{} { return new(T) }
+
+// NewPayloadOf returns a new, unconfigured, PayloadOf.
+func NewPayloadOf[O, A any]() *PayloadOf[O, A] { return new(PayloadOf[O, A]) }
+
+// Reset clears all configurations and values. After this operation the object
+// gets to an initial state.
+func (p *PayloadOf[O, A]) Reset() { p.p.Reset() }
+
+// Clear removes all the associated data saved in the PayloadOf but keeping all
+// the configurations.
+func (p *PayloadOf[O, A]) Clear() { p.p.Clear() }
+
+// UnmarshalJSON implements the JSON Unmarshaler interface.
+func (p *PayloadOf[O, A]) UnmarshalJSON(b []byte) error {
+	return p.p.UnmarshalJSON(b)
+}
+
+// MarshalJSON implements the JSON Marshaler interface.
+func (p *PayloadOf[O, A]) MarshalJSON() ([]byte, error) {
+	return p.p.MarshalJSON()
+}
+
+// GetObject retrieves the value as an O.
+//
+// It panics if the JSON Data Type was not an Object, or if it was not decoded
+// because of WithLazyDecoding. In that case, use DecodeInto instead.
+func (p *PayloadOf[O, A]) GetObject() O {
+	if p.p.jsonType != Object || p.p.mapping != GoOther {
+		panic(ErrUnexpectedMapping)
+	}
+	return *p.p.GetOther().(*O)
+}
+
+// GetArray retrieves the value as an A.
+//
+// It panics if the JSON Data Type was not an Array, or if it was not decoded
+// because of WithLazyDecoding. In that case, use DecodeInto instead.
+func (p *PayloadOf[O, A]) GetArray() A {
+	if p.p.jsonType != Array || p.p.mapping != GoOther {
+		panic(ErrUnexpectedMapping)
+	}
+	return *p.p.GetOther().(*A)
+}
+
+// GetString retrieves the value as a string. See Payload.GetString.
+func (p *PayloadOf[O, A]) GetString() string { return p.p.GetString() }
+
+// GetBool retrieves the value as a bool. See Payload.GetBool.
+func (p *PayloadOf[O, A]) GetBool() bool { return p.p.GetBool() }
+
+// GetInt retrieves the value as an int64. See Payload.GetInt.
+func (p *PayloadOf[O, A]) GetInt() int64 { return p.p.GetInt() }
+
+// GetUint retrieves the value as a uint64. See Payload.GetUint.
+func (p *PayloadOf[O, A]) GetUint() uint64 { return p.p.GetUint() }
+
+// GetFloat retrieves the value as a float64. See Payload.GetFloat.
+func (p *PayloadOf[O, A]) GetFloat() float64 { return p.p.GetFloat() }
+
+// GetJSONNumber retrieves the value as a json.Number. See
+// Payload.GetJSONNumber.
+func (p *PayloadOf[O, A]) GetJSONNumber() json.Number {
+	return p.p.GetJSONNumber()
+}
+
+// GetBigInt retrieves the value as a *big.Int. See Payload.GetBigInt.
+func (p *PayloadOf[O, A]) GetBigInt() *big.Int { return p.p.GetBigInt() }
+
+// GetBigFloat retrieves the value as a *big.Float. See Payload.GetBigFloat.
+func (p *PayloadOf[O, A]) GetBigFloat() *big.Float { return p.p.GetBigFloat() }
+
+// GetBigRat retrieves the value as a *big.Rat. See Payload.GetBigRat.
+func (p *PayloadOf[O, A]) GetBigRat() *big.Rat { return p.p.GetBigRat() }
+
+// GetRaw returns a copy of the input that was last unmarshaled. See
+// Payload.GetRaw.
+func (p *PayloadOf[O, A]) GetRaw() json.RawMessage { return p.p.GetRaw() }
+
+// DecodeInto decodes the raw input retained into v. It's the way to retrieve
+// JSON Object and Array values when WithLazyDecoding is used, in which case v
+// would usually be an *O or an *A. See Payload.DecodeInto.
+func (p *PayloadOf[O, A]) DecodeInto(v interface{}) error {
+	return p.p.DecodeInto(v)
+}
+
+// IsNil reports whether the value was a JSON Null. See Payload.IsNil.
+func (p *PayloadOf[O, A]) IsNil() bool { return p.p.IsNil() }
+
+// GetMapping returns the value mapping.
+func (p *PayloadOf[O, A]) GetMapping() GoMapping { return p.p.GetMapping() }
+
+// GetJSONType returns the JSON Data Type associated with the decoded data. See
+// Payload.GetJSONType.
+func (p *PayloadOf[O, A]) GetJSONType() JSONType { return p.p.GetJSONType() }
+
+// SetNull sets the value to JSON Null. See Payload.SetNull.
+func (p *PayloadOf[O, A]) SetNull(force ...bool) error {
+	return p.p.SetNull(force...)
+}
+
+// SetBool sets the value to a JSON Boolean. See Payload.SetBool.
+func (p *PayloadOf[O, A]) SetBool(v bool, force ...bool) error {
+	return p.p.SetBool(v, force...)
+}
+
+// SetString sets the value to a JSON String. See Payload.SetString.
+func (p *PayloadOf[O, A]) SetString(v string, force ...bool) error {
+	return p.p.SetString(v, force...)
+}
+
+// SetInt sets the value to a JSON Number held as an int64. See Payload.SetInt.
+func (p *PayloadOf[O, A]) SetInt(v int64, force ...bool) error {
+	return p.p.SetInt(v, force...)
+}
+
+// SetUint sets the value to a JSON Number held as a uint64. See
+// Payload.SetUint.
+func (p *PayloadOf[O, A]) SetUint(v uint64, force ...bool) error {
+	return p.p.SetUint(v, force...)
+}
+
+// SetFloat sets the value to a JSON Number held as a float64. See
+// Payload.SetFloat.
+func (p *PayloadOf[O, A]) SetFloat(v float64, force ...bool) error {
+	return p.p.SetFloat(v, force...)
+}
+
+// SetJSONNumber sets the value to a JSON Number held as a json.Number. See
+// Payload.SetJSONNumber.
+func (p *PayloadOf[O, A]) SetJSONNumber(v json.Number, force ...bool) error {
+	return p.p.SetJSONNumber(v, force...)
+}
+
+// SetBigInt sets the value to a JSON Number held as a *big.Int. See
+// Payload.SetBigInt.
+func (p *PayloadOf[O, A]) SetBigInt(v *big.Int, force ...bool) error {
+	return p.p.SetBigInt(v, force...)
+}
+
+// SetBigFloat sets the value to a JSON Number held as a *big.Float. See
+// Payload.SetBigFloat.
+func (p *PayloadOf[O, A]) SetBigFloat(v *big.Float, force ...bool) error {
+	return p.p.SetBigFloat(v, force...)
+}
+
+// SetBigRat sets the value to a JSON Number held as a *big.Rat. See
+// Payload.SetBigRat.
+func (p *PayloadOf[O, A]) SetBigRat(v *big.Rat, force ...bool) error {
+	return p.p.SetBigRat(v, force...)
+}
+
+// SetObject sets the value to a JSON Object. See Payload.SetObject.
+func (p *PayloadOf[O, A]) SetObject(v O, force ...bool) error {
+	return p.p.SetObject(&v, force...)
+}
+
+// SetArray sets the value to a JSON Array. See Payload.SetArray.
+func (p *PayloadOf[O, A]) SetArray(v A, force ...bool) error {
+	return p.p.SetArray(&v, force...)
+}
+
+// WithNull configures the PayloadOf to accept a JSON Null value. See
+// Payload.WithNull.
+func (p *PayloadOf[O, A]) WithNull(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithNull(enable...)
+	return p
+}
+
+// WithBoolean configures the PayloadOf to accept a JSON Boolean value. See
+// Payload.WithBoolean.
+func (p *PayloadOf[O, A]) WithBoolean(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithBoolean(enable...)
+	return p
+}
+
+// WithString configures the PayloadOf to accept a JSON String value. See
+// Payload.WithString.
+func (p *PayloadOf[O, A]) WithString(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithString(enable...)
+	return p
+}
+
+// WithNumber configures the PayloadOf to accept a JSON Number value. See
+// Payload.WithNumber.
+func (p *PayloadOf[O, A]) WithNumber(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithNumber(enable...)
+	return p
+}
+
+// WithFloat configures the PayloadOf to accept a JSON Number value and
+// interpret it as a float64. See Payload.WithFloat.
+func (p *PayloadOf[O, A]) WithFloat(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithFloat(enable...)
+	return p
+}
+
+// WithInt configures the PayloadOf to accept a JSON Number value and interpret
+// it as a int64. See Payload.WithInt.
+func (p *PayloadOf[O, A]) WithInt(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithInt(enable...)
+	return p
+}
+
+// WithUint configures the PayloadOf to accept a JSON Number value and
+// interpret it as a uint64. See Payload.WithUint.
+func (p *PayloadOf[O, A]) WithUint(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithUint(enable...)
+	return p
+}
+
+// WithAutoNumber configures the PayloadOf to accept a JSON Number value and
+// choose how to interpret each value. See Payload.WithAutoNumber.
+func (p *PayloadOf[O, A]) WithAutoNumber(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithAutoNumber(enable...)
+	return p
+}
+
+// WithJSONNumber configures the PayloadOf to accept a JSON Number value and
+// interpret it as a json.Number. See Payload.WithJSONNumber.
+func (p *PayloadOf[O, A]) WithJSONNumber(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithJSONNumber(enable...)
+	return p
+}
+
+// WithBigInt configures the PayloadOf to accept a JSON Number value and
+// interpret it as a *big.Int. See Payload.WithBigInt.
+func (p *PayloadOf[O, A]) WithBigInt(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithBigInt(enable...)
+	return p
+}
+
+// WithBigFloat configures the PayloadOf to accept a JSON Number value and
+// interpret it as a *big.Float. See Payload.WithBigFloat.
+func (p *PayloadOf[O, A]) WithBigFloat(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithBigFloat(enable...)
+	return p
+}
+
+// WithBigRat configures the PayloadOf to accept a JSON Number value and
+// interpret it as a *big.Rat. See Payload.WithBigRat.
+func (p *PayloadOf[O, A]) WithBigRat(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithBigRat(enable...)
+	return p
+}
+
+// WithRetainRaw configures the PayloadOf to keep a copy of the input. See
+// Payload.WithRetainRaw.
+func (p *PayloadOf[O, A]) WithRetainRaw(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithRetainRaw(enable...)
+	return p
+}
+
+// WithLazyDecoding configures the PayloadOf to leave JSON Array and Object
+// values undecoded, so that they are only decoded on demand with DecodeInto.
+// GetObject and GetArray cannot be used for those values. See
+// Payload.WithLazyDecoding.
+func (p *PayloadOf[O, A]) WithLazyDecoding(enable ...bool) *PayloadOf[O, A] {
+	p.p.WithLazyDecoding(enable...)
+	return p
+}
+
+// WithObject configures the PayloadOf to accept a JSON Object value (disabled
+// by default), which will be decoded into an O.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *PayloadOf[O, A]) WithObject(enable ...bool) *PayloadOf[O, A] {
+	if len(enable) == 0 || enable[0] {
+		p.p.WithObject(factoryOf[O])
+	} else {
+		p.p.WithObject(nil)
+	}
+	return p
+}
+
+// WithArray configures the PayloadOf to accept a JSON Array value (disabled by
+// default), which will be decoded into an A.
+//
+// The default behavior when calling this method is to enable this
+// configuration.
+func (p *PayloadOf[O, A]) WithArray(enable ...bool) *PayloadOf[O, A] {
+	if len(enable) == 0 || enable[0] {
+		p.p.WithArray(factoryOf[A])
+	} else {
+		p.p.WithArray(nil)
+	}
+	return p
+}
@@ -0,0 +1,207 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestPayloadOf(t *testing.T) {
+	type Response struct {
+		Status int                    `json:"status"`
+		Data   *PayloadOf[Tag, []Tag] `json:"data"`
+	}
+
+	resp := Response{Data: NewPayloadOf[Tag, []Tag]()}
+	resp.Data.WithObject().WithArray().WithString()
+
+	in := `{"status":200,"data":{"key":"K","value":"V"}}`
+	if err := json.Unmarshal([]byte(in), &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := resp.Data.GetObject(); val != (Tag{Key: "K", Value: "V"}) {
+		t.Fatalf("Want: %v; Have: %v", Tag{Key: "K", Value: "V"}, val)
+	}
+	if b, _ := json.Marshal(resp); string(b) != in {
+		t.Fatalf("Want: %s; Have: %s", in, b)
+	}
+
+	in = `{"status":200,"data":[{"key":"K","value":"V"},{"key":"K1","value":"V1"}]}`
+	if err := json.Unmarshal([]byte(in), &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := resp.Data.GetArray(); len(val) != 2 || val[1].Key != "K1" {
+		t.Fatalf("Unexpected value: %v", val)
+	}
+	if b, _ := json.Marshal(resp); string(b) != in {
+		t.Fatalf("Want: %s; Have: %s", in, b)
+	}
+
+	// An Array cannot be retrieved as an Object.
+	var panicVal interface{}
+	func() {
+		defer func() {
+			panicVal = recover()
+		}()
+		resp.Data.GetObject()
+	}()
+	if panicVal != ErrUnexpectedMapping {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedMapping, panicVal)
+	}
+
+	// Disabled types are rejected.
+	resp.Data.WithArray(false)
+	err := resp.Data.UnmarshalJSON([]byte(`[]`))
+	if !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+
+	if err = resp.Data.SetArray([]Tag{{Key: "K2"}}, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := resp.Data.GetArray(); len(val) != 1 || val[0].Key != "K2" {
+		t.Fatalf("Unexpected value: %v", val)
+	}
+	if err = resp.Data.SetObject(Tag{Key: "K3"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if val := resp.Data.GetObject(); val.Key != "K3" {
+		t.Fatalf("Unexpected value: %v", val)
+	}
+	func() {
+		defer func() {
+			panicVal = recover()
+		}()
+		resp.Data.GetArray()
+	}()
+	if panicVal != ErrUnexpectedMapping {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedMapping, panicVal)
+	}
+
+	resp.Data.WithObject(false)
+	if err = resp.Data.SetObject(Tag{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+
+	// Lazy values are retrieved with DecodeInto.
+	resp.Data.WithObject().WithLazyDecoding()
+	in = `{"key":"K4"}`
+	if err = resp.Data.UnmarshalJSON([]byte(in)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var tag Tag
+	if err = resp.Data.DecodeInto(&tag); err != nil || tag.Key != "K4" {
+		t.Fatalf("Unexpected value: %v (error: %v)", tag, err)
+	}
+	if b, _ := resp.Data.MarshalJSON(); string(b) != in {
+		t.Fatalf("Want: %s; Have: %s", in, b)
+	}
+	func() {
+		defer func() {
+			panicVal = recover()
+		}()
+		resp.Data.GetObject()
+	}()
+	if panicVal != ErrUnexpectedMapping {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedMapping, panicVal)
+	}
+}
+
+func TestPayloadOf_Scalars(t *testing.T) {
+	var p PayloadOf[Tag, []Tag]
+	p.WithNull().WithBoolean().WithString().WithNumber()
+
+	tests := []struct {
+		JSONData string
+		Check    func() bool
+	}{
+		{`null`, p.IsNil},
+		{`true`, func() bool { return p.GetBool() }},
+		{`"s"`, func() bool { return p.GetString() == "s" }},
+		{`-2`, func() bool { return p.GetInt() == -2 }},
+	}
+	for _, test := range tests {
+		if err := p.UnmarshalJSON([]byte(test.JSONData)); err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.JSONData, err)
+		}
+		if !test.Check() {
+			t.Fatalf("[%s] Unexpected value", test.JSONData)
+		}
+		if p.GetJSONType() == InvalidJSON || p.GetMapping() == GoInvalidMapping {
+			t.Fatalf("[%s] Unexpected type or mapping", test.JSONData)
+		}
+	}
+
+	p.WithUint()
+	if err := p.UnmarshalJSON([]byte(`3`)); err != nil || p.GetUint() != 3 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithFloat()
+	if err := p.UnmarshalJSON([]byte(`3.5`)); err != nil || p.GetFloat() != 3.5 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithAutoNumber()
+	if err := p.UnmarshalJSON([]byte(`3`)); err != nil || p.GetInt() != 3 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.UnmarshalJSON([]byte(`3.5`)); err != nil || p.GetFloat() != 3.5 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithJSONNumber()
+	if err := p.UnmarshalJSON([]byte(`3.50`)); err != nil || p.GetJSONNumber() != "3.50" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithBigInt()
+	if err := p.UnmarshalJSON([]byte(`3`)); err != nil || p.GetBigInt().Int64() != 3 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithBigFloat()
+	if err := p.UnmarshalJSON([]byte(`3.5`)); err != nil || p.GetBigFloat().String() != "3.5" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithBigRat()
+	if err := p.UnmarshalJSON([]byte(`3.5`)); err != nil || p.GetBigRat().String() != "7/2" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	p.WithInt()
+	if err := p.SetInt(7); err != nil || p.GetInt() != 7 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetUint(8); err != nil || p.GetUint() != 8 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetFloat(.5); err != nil || p.GetFloat() != .5 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetJSONNumber("1e2"); err != nil || p.GetJSONNumber() != "1e2" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetBigInt(big.NewInt(9)); err != nil || p.GetBigInt().Int64() != 9 {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetBigFloat(big.NewFloat(.25)); err != nil || p.GetBigFloat().String() != "0.25" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetBigRat(big.NewRat(1, 4)); err != nil || p.GetBigRat().String() != "1/4" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetString("s"); err != nil || p.GetString() != "s" {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetBool(true); err != nil || !p.GetBool() {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+	if err := p.SetNull(); err != nil || !p.IsNil() {
+		t.Fatalf("Unexpected result: %v, %v", p.GetMapping(), err)
+	}
+
+	p.Clear()
+	if p.GetMapping() != GoInvalidMapping {
+		t.Fatalf("Want: %v; Have: %v", GoInvalidMapping, p.GetMapping())
+	}
+	p.Reset()
+	if err := p.UnmarshalJSON([]byte(`null`)); !errors.Is(err, ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+}
@@ -0,0 +1,52 @@
+package jsonutils
+
+import "sync"
+
+// PayloadSpec is an immutable Payload configuration, which can be built once
+// and safely shared by many goroutines. Each PayloadSpec has its own pool of
+// Payload objects already configured with it, so acquiring and releasing them
+// skips both the configuration and the Reset work.
+//
+// Typically, you would do something like this:
+//
+//	var userSpec = NewPayloadSpec(new(Payload).WithString().WithObject(
+//		func() interface{} { return new(User) }))
+//
+//	func handle(b []byte) error {
+//		p := userSpec.Acquire()
+//		defer ReleasePayload(p)
+//		return json.Unmarshal(b, p)
+//	}
+//
+// The PayloadFactory functions configured are shared by all the Payload
+// objects, so they must be safe for concurrent use.
+type PayloadSpec struct {
+	_      noCopy
+	config payloadConfig
+	pool   sync.Pool
+}
+
+// NewPayloadSpec returns a new PayloadSpec with a snapshot of the
+// configuration of p. Later changes to p don't affect the PayloadSpec.
+func NewPayloadSpec(p *Payload) *PayloadSpec {
+	return &PayloadSpec{config: p.payloadConfig}
+}
+
+// Acquire returns a Payload configured with this PayloadSpec, which should be
+// passed to ReleasePayload when it's no longer needed.
+//
+// The returned Payload can be reconfigured, but its configuration will be
+// restored to this PayloadSpec when it's released.
+func (s *PayloadSpec) Acquire() *Payload {
+	if p := s.pool.Get(); p != nil {
+		return p.(*Payload)
+	}
+	return &Payload{payloadConfig: s.config, spec: s}
+}
+
+// release clears p and returns it to the pool.
+func (s *PayloadSpec) release(p *Payload) {
+	p.Clear()
+	p.payloadConfig = s.config
+	s.pool.Put(p)
+}
@@ -0,0 +1,106 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestPayloadSpec(t *testing.T) {
+	cfg := new(Payload).WithString().WithObject(func() interface{} {
+		return new(Tag)
+	})
+	spec := NewPayloadSpec(cfg)
+
+	// Later changes to the original Payload don't affect the PayloadSpec.
+	cfg.WithNull()
+
+	p := spec.Acquire()
+	if err := p.UnmarshalJSON([]byte(`{"key":"K"}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tag := p.GetObject().(*Tag); tag.Key != "K" {
+		t.Fatalf("Unexpected value: %v", tag)
+	}
+	if err := p.UnmarshalJSON([]byte(`null`)); !errors.Is(err,
+		ErrUnexpectedType) {
+		t.Fatalf("Want: %v; Have: %v", ErrUnexpectedType, err)
+	}
+
+	// Reconfiguring a Payload doesn't affect the PayloadSpec.
+	p.WithNull().WithString(false)
+	ReleasePayload(p)
+	for i := 0; i < 10; i++ {
+		p = spec.Acquire()
+		if p.GetMapping() != GoInvalidMapping {
+			t.Fatalf("Payload not cleared: %v", p.GetMapping())
+		}
+		if !p.with[String] || p.with[Null] {
+			t.Fatalf("Payload configuration not restored: %v", p.with)
+		}
+		ReleasePayload(p)
+	}
+
+	// Reset detaches the Payload from the PayloadSpec.
+	p = spec.Acquire()
+	p.Reset()
+	if p.spec != nil || p.with[String] {
+		t.Fatalf("Payload not reset: %#v", p)
+	}
+	ReleasePayload(p)
+}
+
+func TestPayloadSpec_Concurrent(t *testing.T) {
+	spec := NewPayloadSpec(new(Payload).WithInt().WithString())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				var resp struct {
+					Data *Payload `json:"data"`
+				}
+				resp.Data = spec.Acquire()
+				err := json.Unmarshal([]byte(`{"data":42}`), &resp)
+				if err != nil || resp.Data.GetInt() != 42 {
+					t.Errorf("Unexpected result: %v (error: %v)",
+						resp.Data.GetMapping(), err)
+				}
+				ReleasePayload(resp.Data)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkPayloadSpec(b *testing.B) {
+	data := []byte(`"Lorem ipsum"`)
+	factory := func() interface{} { return new(Tag) }
+
+	b.Run("AcquirePayload", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p := AcquirePayload().WithString().WithObject(factory).WithNull()
+			if err := p.UnmarshalJSON(data); err != nil {
+				b.Fatal(err)
+			}
+			ReleasePayload(p)
+		}
+	})
+
+	b.Run("PayloadSpec", func(b *testing.B) {
+		spec := NewPayloadSpec(new(Payload).WithString().WithObject(factory).
+			WithNull())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p := spec.Acquire()
+			if err := p.UnmarshalJSON(data); err != nil {
+				b.Fatal(err)
+			}
+			ReleasePayload(p)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Pointer is a JSON Pointer (RFC 6901), which identifies a specific value
+// within a JSON document. It holds the reference tokens already unescaped, so
+// a Pointer can also be built directly:
+//
+//	p := Pointer{"users", "0", "email"} // Same as ParsePointer("/users/0/email")
+//
+// The empty Pointer refers to the whole document.
+type Pointer []string
+
+// PointerError describes a JSON Pointer that could not be resolved, pointing
+// at the reference token where resolving it stopped.
+type PointerError struct {
+	// Pointer is the JSON Pointer that failed.
+	Pointer Pointer
+	// Index is the position in Pointer of the reference token that failed.
+	Index int
+	// Token is the reference token that failed.
+	Token string
+	// Err is the underlying error, like ErrNotFound or ErrInvalidIndex.
+	Err error
+}
+
+func (e *PointerError) Error() string {
+	return fmt.Sprintf("pointer %q: token %q: %v", e.Pointer.String(), e.Token,
+		e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PointerError) Unwrap() error { return e.Err }
+
+// pointerEscaper and pointerUnescaper handle the escaping of reference tokens.
+// The order of the replacements matters, as explained in RFC 6901 section 4.
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// ParsePointer parses the string representation of a JSON Pointer, unescaping
+// its reference tokens. It returns an error matching ErrInvalidPointer if s is
+// not empty and doesn't start with '/', or if it has a '~' not followed by '0'
+// or '1'.
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, s)
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, tok := range tokens {
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 >= len(tok) ||
+				tok[j+1] != '0' && tok[j+1] != '1') {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, s)
+			}
+		}
+		tokens[i] = pointerUnescaper.Replace(tok)
+	}
+
+	return Pointer(tokens), nil
+}
+
+// String returns the string representation of the JSON Pointer, escaping its
+// reference tokens.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, tok := range p {
+		sb.WriteByte('/')
+		pointerEscaper.WriteString(&sb, tok)
+	}
+	return sb.String()
+}
+
+// Get returns the bytes of the value referenced by p within the JSON document
+// doc, and its JSON Data Type. The bytes returned are a subslice of doc.
+//
+// Only the parts of doc needed to reach the value are scanned, and nothing is
+// decoded. As a consequence, doc is not fully validated. If an Object has
+// duplicate member names, then the first member is used.
+//
+// It returns a *SyntaxError if the scanned parts of doc are not valid, and a
+// *PointerError if p could not be resolved.
+func (p Pointer) Get(doc []byte) ([]byte, JSONType, error) {
+	start := skipSpace(doc, 0)
+	end := start + len(trimSpace(doc[start:]))
+	if len(p) == 0 {
+		// Nothing else would validate the whole document.
+		t, i, err := scanValue(doc[:end], start, 0)
+		if err == nil && i != end {
+			err = unexpectedAt(doc, i)
+		}
+		if err != nil {
+			return nil, InvalidJSON, err
+		}
+		return doc[start:end], t, nil
+	}
+
+	if start == end {
+		return nil, InvalidJSON, unexpectedAt(doc, start)
+	}
+	start, end, err := p.span(doc, start, end)
+	if err != nil {
+		return nil, InvalidJSON, err
+	}
+	t, _ := TypeOf(doc[start:end])
+	return doc[start:end], t, nil
+}
+
+// span returns the positions where the value referenced by p within the value
+// in doc[start:end] starts and ends.
+func (p Pointer) span(doc []byte, start, end int) (int, int, error) {
+	for i, tok := range p {
+		var found bool
+		var err error
+		switch doc[start] {
+		case '{':
+			start, end, found, err = memberSpan(doc, start, end, i+1, tok)
+
+		case '[':
+			var idx int
+			if idx, err = p.index(i, -1); err != nil {
+				return 0, 0, err
+			}
+			start, end, found, err = elementSpan(doc, start, end, i+1, idx)
+
+		default:
+			t, _ := TypeOf(doc[start:end])
+			return 0, 0, p.errorAt(i, &UnexpectedTypeError{Got: t,
+				Allowed: []JSONType{Object, Array}, Offset: int64(start)})
+		}
+
+		if err != nil {
+			return 0, 0, err
+		}
+		if !found {
+			return 0, 0, p.errorAt(i, ErrNotFound)
+		}
+	}
+
+	return start, end, nil
+}
+
+// GetValue returns the value referenced by p within v, which is a tree of
+// decoded JSON values, like those produced by encoding/json when decoding into
+// an interface{}: map[string]interface{} for Objects and []interface{} for
+// Arrays. Pointers to those types are also traversed, like the values created
+// by WithDefaultObject and WithDefaultArray.
+//
+// It returns a *PointerError if p could not be resolved.
+func (p Pointer) GetValue(v interface{}) (interface{}, error) {
+	for i, tok := range p {
+		switch x := derefValue(v).(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = x[tok]; !ok {
+				return nil, p.errorAt(i, ErrNotFound)
+			}
+
+		case []interface{}:
+			idx, err := p.index(i, len(x))
+			if err != nil {
+				return nil, err
+			}
+			v = x[idx]
+
+		default:
+			return nil, p.errorAt(i, &UnexpectedTypeError{Got: valueType(v),
+				Allowed: []JSONType{Object, Array}})
+		}
+	}
+	return v, nil
+}
+
+// index parses the reference token at position i of p as an Array index. If n
+// is not negative, then the index must also be less than n.
+func (p Pointer) index(i, n int) (int, error) {
+	if p[i] == "-" {
+		// "-" references the (nonexistent) element after the last one.
+		return 0, p.errorAt(i, ErrNotFound)
+	}
+	idx, ok := parseIndex(p[i])
+	if !ok {
+		return 0, p.errorAt(i, ErrInvalidIndex)
+	}
+	if n >= 0 && idx >= n {
+		return 0, p.errorAt(i, ErrNotFound)
+	}
+	return idx, nil
+}
+
+// errorAt returns a *PointerError for the reference token at position i.
+func (p Pointer) errorAt(i int, err error) error {
+	return &PointerError{Pointer: p, Index: i, Token: p[i], Err: err}
+}
+
+// derefValue returns the value pointed to by v if it's a pointer to a
+// map[string]interface{} or a []interface{}, and v otherwise.
+func derefValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case *map[string]interface{}:
+		if x != nil {
+			return *x
+		}
+	case *[]interface{}:
+		if x != nil {
+			return *x
+		}
+	}
+	return v
+}
+
+// valueType returns the JSON Data Type that a decoded JSON value would be
+// encoded as, or InvalidJSON if it's not a type produced by encoding/json.
+func valueType(v interface{}) JSONType {
+	switch derefValue(v).(type) {
+	case nil:
+		return Null
+	case map[string]interface{}:
+		return Object
+	case []interface{}:
+		return Array
+	case string:
+		return String
+	case float64, json.Number:
+		return Number
+	case bool:
+		return Boolean
+	}
+	return InvalidJSON
+}
@@ -0,0 +1,177 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// rfc6901Doc is the example document from RFC 6901 section 5.
+const rfc6901Doc = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+var PointerTestsRaw = []struct {
+	Pointer string
+	Raw     string
+	JSONType
+	Error error
+}{
+	{``, rfc6901Doc, Object, nil},
+	{`/foo`, `["bar", "baz"]`, Array, nil},
+	{`/foo/0`, `"bar"`, String, nil},
+	{`/`, `0`, Number, nil},
+	{`/a~1b`, `1`, Number, nil},
+	{`/c%d`, `2`, Number, nil},
+	{`/e^f`, `3`, Number, nil},
+	{`/g|h`, `4`, Number, nil},
+	{`/i\j`, `5`, Number, nil},
+	{`/k"l`, `6`, Number, nil},
+	{`/ `, `7`, Number, nil},
+	{`/m~0n`, `8`, Number, nil},
+
+	{`/bar`, ``, InvalidJSON, ErrNotFound},
+	{`/foo/2`, ``, InvalidJSON, ErrNotFound},
+	{`/foo/-`, ``, InvalidJSON, ErrNotFound},
+	{`/foo/99999999999999999999`, ``, InvalidJSON, ErrNotFound},
+	{`/foo/01`, ``, InvalidJSON, ErrInvalidIndex},
+	{`/foo/a`, ``, InvalidJSON, ErrInvalidIndex},
+	{`/foo/`, ``, InvalidJSON, ErrInvalidIndex},
+	{`/foo/0/x`, ``, InvalidJSON, ErrUnexpectedType},
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  Pointer
+		Error error
+	}{
+		{``, Pointer{}, nil},
+		{`/`, Pointer{""}, nil},
+		{`/a~1b/m~0n/~01`, Pointer{"a/b", "m~n", "~1"}, nil},
+		{`//x/`, Pointer{"", "x", ""}, nil},
+		{`a`, nil, ErrInvalidPointer},
+		{`/a~`, nil, ErrInvalidPointer},
+		{`/a~2`, nil, ErrInvalidPointer},
+	}
+
+	for _, test := range tests {
+		p, err := ParsePointer(test.Input)
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Input, test.Error,
+				err)
+		}
+		if !reflect.DeepEqual(p, test.Want) {
+			t.Fatalf("[%s] Want: %q; Have: %q", test.Input, test.Want, p)
+		}
+		if err == nil && p.String() != test.Input {
+			t.Fatalf("Want: %s; Have: %s", test.Input, p.String())
+		}
+	}
+}
+
+func TestPointer_Get(t *testing.T) {
+	for _, test := range PointerTestsRaw {
+		p, err := ParsePointer(test.Pointer)
+		if err != nil {
+			t.Fatalf("[%s] Unexpected error: %v", test.Pointer, err)
+		}
+		raw, jType, err := p.Get([]byte(" " + rfc6901Doc + "\n"))
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Pointer, test.Error,
+				err)
+		}
+		if string(raw) != test.Raw || jType != test.JSONType {
+			t.Fatalf("[%s] Want: %s (%v); Have: %s (%v)", test.Pointer,
+				test.Raw, test.JSONType, raw, jType)
+		}
+	}
+}
+
+func TestPointer_GetValue(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(rfc6901Doc), &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, test := range PointerTestsRaw {
+		p, _ := ParsePointer(test.Pointer)
+		v, err := p.GetValue(doc)
+		if !errors.Is(err, test.Error) {
+			t.Fatalf("[%s] Want error: %v; Have: %v", test.Pointer, test.Error,
+				err)
+		}
+		if err != nil {
+			continue
+		}
+		var want interface{}
+		if err = json.Unmarshal([]byte(test.Raw), &want); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(v, want) {
+			t.Fatalf("[%s] Want: %v; Have: %v", test.Pointer, want, v)
+		}
+	}
+
+	// Pointers created by the default factories are traversed.
+	obj := WithDefaultObject().(*map[string]interface{})
+	*obj = map[string]interface{}{"a": &[]interface{}{"b"}}
+	if v, err := (Pointer{"a", "0"}).GetValue(obj); err != nil || v != "b" {
+		t.Fatalf("Unexpected result: %v (error: %v)", v, err)
+	}
+}
+
+func TestPointer_Errors(t *testing.T) {
+	var ptrErr *PointerError
+	doc := []byte(`{"a":{"b":[true]}}`)
+
+	_, _, err := Pointer{"a", "b", "0", "c"}.Get(doc)
+	if !errors.As(err, &ptrErr) || ptrErr.Index != 3 || ptrErr.Token != "c" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `pointer "/a/b/0/c": token "c": expected one of [object array], ` +
+		`got boolean`
+	if err.Error() != want {
+		t.Fatalf("Want: %s; Have: %s", want, err)
+	}
+
+	_, _, err = Pointer{"a", "x~y"}.Get(doc)
+	if !errors.As(err, &ptrErr) || ptrErr.Index != 1 || ptrErr.Token != "x~y" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = `pointer "/a/x~0y": token "x~y": value not found`
+	if err.Error() != want {
+		t.Fatalf("Want: %s; Have: %s", want, err)
+	}
+
+	// Invalid documents.
+	for _, in := range []string{``, ` `, `{"a":}`, `{"a" 1}`, `[] x`} {
+		if _, _, err = (Pointer{}).Get([]byte(in)); err == nil {
+			t.Fatalf("[%s] Want an error", in)
+		}
+		if _, _, err = (Pointer{"a"}).Get([]byte(in)); err == nil {
+			t.Fatalf("[%s] Want an error", in)
+		}
+	}
+}
+
+func BenchmarkPointer_Get(b *testing.B) {
+	doc := []byte(rfc6901Doc)
+	p := Pointer{"m~n"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := p.Get(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package jsonutils
+
+import (
+	"bytes"
+	"math"
+)
+
+// maxNestingDepth is the maximum number of nested Arrays and Objects allowed
+// when scanning. It's the same limit used by encoding/json.
+const maxNestingDepth = 10000
+
+// The scan* functions below validate a JSON value starting at b[i], which must
+// not be whitespace, and return the position right after it. They follow the
+// RFC 8259 grammar, never allocate unless an error is found, and return a
+// *SyntaxError on the first violation.
+
+// scanValue scans any JSON value, returning also its JSON Data Type.
+func scanValue(b []byte, i, depth int) (JSONType, int, error) {
+	if i >= len(b) {
+		return InvalidJSON, i, unexpectedAt(b, i)
+	}
+
+	var err error
+	switch c := b[i]; {
+	case c == '{':
+		i, err = scanObject(b, i, depth+1)
+		return Object, i, err
+	case c == '[':
+		i, err = scanArray(b, i, depth+1)
+		return Array, i, err
+	case c == '"':
+		i, err = scanString(b, i)
+		return String, i, err
+	case c == 'n':
+		i, err = scanLiteral(b, i, bNull)
+		return Null, i, err
+	case c == 't':
+		i, err = scanLiteral(b, i, bTrue)
+		return Boolean, i, err
+	case c == 'f':
+		i, err = scanLiteral(b, i, bFalse)
+		return Boolean, i, err
+	case c == '-', '0' <= c && c <= '9':
+		_, i, err = scanNumber(b, i)
+		return Number, i, err
+	}
+
+	return InvalidJSON, i, unexpectedAt(b, i)
+}
+
+// unexpectedAt returns a *SyntaxError for an unexpected byte at b[i], or for
+// an unexpected end of input if i is past the end of b.
+func unexpectedAt(b []byte, i int) error {
+	if i >= len(b) {
+		return newSyntaxError(b, i, ErrUnexpectedEnd)
+	}
+	return newSyntaxError(b, i, ErrInvalidJSON)
+}
+
+// expectByte skips whitespace from b[i] and checks that the next byte is c,
+// returning the position right after it.
+func expectByte(b []byte, i int, c byte) (int, error) {
+	i = skipSpace(b, i)
+	if i >= len(b) || b[i] != c {
+		return i, unexpectedAt(b, i)
+	}
+	return i + 1, nil
+}
+
+func scanObject(b []byte, i, depth int) (int, error) {
+	if depth > maxNestingDepth {
+		return i, newSyntaxError(b, i, ErrTooDeep)
+	}
+	return eachMember(b, i, depth, nil)
+}
+
+func scanArray(b []byte, i, depth int) (int, error) {
+	if depth > maxNestingDepth {
+		return i, newSyntaxError(b, i, ErrTooDeep)
+	}
+	return eachElement(b, i, depth, nil)
+}
+
+// scanString scans a JSON String. Like encoding/json, it accepts invalid UTF-8,
+// which is replaced when decoding.
+func scanString(b []byte, i int) (int, error) {
+	for i++; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == '"':
+			return i + 1, nil
+		case c < ' ':
+			return i, unexpectedAt(b, i)
+		case c == '\\':
+			if i++; i >= len(b) {
+				return i, unexpectedAt(b, i)
+			}
+			switch b[i] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				for j := 0; j < 4; j++ {
+					if i++; i >= len(b) || !isHex(b[i]) {
+						return i, unexpectedAt(b, i)
+					}
+				}
+			default:
+				return i, unexpectedAt(b, i)
+			}
+		}
+	}
+	return i, unexpectedAt(b, i)
+}
+
+// scanNumber scans a JSON Number, returning also its NumberKind.
+func scanNumber(b []byte, i int) (NumberKind, int, error) {
+	kind := Integer
+	if b[i] == '-' {
+		kind = NegativeInteger
+		i++
+	}
+
+	// Integer part.
+	switch {
+	case i >= len(b):
+		return InvalidNumber, i, unexpectedAt(b, i)
+	case b[i] == '0':
+		i++
+	case '1' <= b[i] && b[i] <= '9':
+		i = skipDigits(b, i+1)
+	default:
+		return InvalidNumber, i, unexpectedAt(b, i)
+	}
+
+	// Fraction part.
+	if i < len(b) && b[i] == '.' {
+		j := skipDigits(b, i+1)
+		if j == i+1 {
+			return InvalidNumber, j, unexpectedAt(b, j)
+		}
+		kind = Fraction
+		i = j
+	}
+
+	// Exponent part.
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		j := skipDigits(b, i)
+		if j == i {
+			return InvalidNumber, j, unexpectedAt(b, j)
+		}
+		kind = Exponent
+		i = j
+	}
+
+	return kind, i, nil
+}
+
+// scanLiteral scans the literal lit (null, true or false).
+func scanLiteral(b []byte, i int, lit []byte) (int, error) {
+	for _, c := range lit {
+		if i >= len(b) || b[i] != c {
+			return i, unexpectedAt(b, i)
+		}
+		i++
+	}
+	return i, nil
+}
+
+func skipDigits(b []byte, i int) int {
+	for i < len(b) && '0' <= b[i] && b[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// eachMember validates the JSON Object starting at b[i], which is at the given
+// nesting depth, calling fn (if not nil) with the positions of the key
+// (including its quotes) and the value of each of its members, in order. If fn
+// returns false then the iteration stops early. Otherwise, it returns the
+// position right after the Object.
+func eachMember(b []byte, i, depth int,
+	fn func(keyStart, keyEnd, valStart, valEnd int) bool) (int, error) {
+	if i >= len(b) || b[i] != '{' {
+		return i, unexpectedAt(b, i)
+	}
+
+	i = skipSpace(b, i+1)
+	if i < len(b) && b[i] == '}' {
+		return i + 1, nil
+	}
+
+	for {
+		if i >= len(b) || b[i] != '"' {
+			return i, unexpectedAt(b, i)
+		}
+		keyStart := i
+		keyEnd, err := scanString(b, i)
+		if err != nil {
+			return keyEnd, err
+		}
+		if i, err = expectByte(b, keyEnd, ':'); err != nil {
+			return i, err
+		}
+		valStart := skipSpace(b, i)
+		if _, i, err = scanValue(b, valStart, depth); err != nil {
+			return i, err
+		}
+		if fn != nil && !fn(keyStart, keyEnd, valStart, i) {
+			return i, nil
+		}
+
+		i = skipSpace(b, i)
+		switch {
+		case i >= len(b) || b[i] != '}' && b[i] != ',':
+			return i, unexpectedAt(b, i)
+		case b[i] == '}':
+			return i + 1, nil
+		}
+		i = skipSpace(b, i+1)
+	}
+}
+
+// eachElement validates the JSON Array starting at b[i], which is at the given
+// nesting depth, calling fn (if not nil) with the positions of each of its
+// elements, in order. If fn returns false then the iteration stops early.
+// Otherwise, it returns the position right after the Array.
+func eachElement(b []byte, i, depth int,
+	fn func(start, end int) bool) (int, error) {
+	if i >= len(b) || b[i] != '[' {
+		return i, unexpectedAt(b, i)
+	}
+
+	i = skipSpace(b, i+1)
+	if i < len(b) && b[i] == ']' {
+		return i + 1, nil
+	}
+
+	for {
+		start := i
+		_, end, err := scanValue(b, start, depth)
+		if err != nil {
+			return end, err
+		}
+		if fn != nil && !fn(start, end) {
+			return end, nil
+		}
+
+		i = skipSpace(b, end)
+		switch {
+		case i >= len(b) || b[i] != ']' && b[i] != ',':
+			return i, unexpectedAt(b, i)
+		case b[i] == ']':
+			return i + 1, nil
+		}
+		i = skipSpace(b, i+1)
+	}
+}
+
+// keyEquals reports whether the quoted JSON String raw is equal to key.
+func keyEquals(raw []byte, key string) bool {
+	s := raw[1 : len(raw)-1]
+	if bytes.IndexByte(s, '\\') < 0 {
+		return string(s) == key
+	}
+	k, err := Unquote(raw)
+	return err == nil && k == key
+}
+
+// memberSpan returns the positions of the value of the first member named key
+// of the JSON Object in b[start:end], which is at the given nesting depth.
+func memberSpan(b []byte, start, end, depth int, key string) (int, int, bool,
+	error) {
+	found := false
+	_, err := eachMember(b[:end], start, depth, func(ks, ke, vs, ve int) bool {
+		if keyEquals(b[ks:ke], key) {
+			found, start, end = true, vs, ve
+		}
+		return !found
+	})
+	return start, end, found, err
+}
+
+// elementSpan returns the positions of the element at index idx of the JSON
+// Array in b[start:end], which is at the given nesting depth.
+func elementSpan(b []byte, start, end, depth, idx int) (int, int, bool,
+	error) {
+	found := false
+	_, err := eachElement(b[:end], start, depth, func(s, e int) bool {
+		if idx == 0 {
+			found, start, end = true, s, e
+		}
+		idx--
+		return !found
+	})
+	return start, end, found, err
+}
+
+// parseIndex parses s as an Array index, which must be a non-negative decimal
+// integer without leading zeros. Indexes too large for an int are clamped to
+// math.MaxInt, since no Array could hold them anyway.
+func parseIndex(s string) (int, bool) {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		if n > (math.MaxInt-9)/10 {
+			n = math.MaxInt
+		} else {
+			n = n*10 + int(s[i]-'0')
+		}
+	}
+	return n, true
+}
+
+// arrayLen returns the number of elements of the JSON Array in b[start:end],
+// which is at the given nesting depth.
+func arrayLen(b []byte, start, end, depth int) (int, error) {
+	n := 0
+	_, err := eachElement(b[:end], start, depth, func(_, _ int) bool {
+		n++
+		return true
+	})
+	return n, err
+}
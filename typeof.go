@@ -2,39 +2,243 @@ package jsonutils
 
 import (
 	"bytes"
-	"strconv"
+	"errors"
+	"io"
+)
+
+// NumberKind classifies a JSON Number according to its syntax, which helps
+// choosing how to map it into a Go type.
+type NumberKind uint8
+
+const (
+	// InvalidNumber is not a kind of number but it's used for control purposes
+	// in this package (like signaling errors).
+	InvalidNumber NumberKind = iota
+
+	// Integer is a number with no sign, fraction nor exponent, like 42. It can
+	// be mapped with WithInt or WithUint, as long as it does not overflow.
+	Integer
+	// NegativeInteger is a number with a minus sign but no fraction nor
+	// exponent, like -42. It can be mapped with WithInt, as long as it does
+	// not overflow.
+	NegativeInteger
+	// Fraction is a number with a fraction but no exponent, like -4.2. It can
+	// be mapped with WithFloat.
+	Fraction
+	// Exponent is a number with an exponent, regardless of having a fraction
+	// or not, like 4.2e1. It can be mapped with WithFloat.
+	Exponent
 )
 
 // TypeOf determines the JSON Data Type of the specified []byte. This comes in
 // handy when needing to decode variable type responses.
 //
+// Leading and trailing insignificant whitespace, as defined by RFC 8259, is
+// skipped. If jsonBytes is empty then ErrEmpty is returned. Otherwise, errors
+// are of type *SyntaxError, which holds the position and value of the
+// offending byte, and wrap either ErrEmpty (if there was only whitespace) or
+// ErrUnknownType.
+//
+// TypeOf never allocates unless an error is returned.
+//
 func TypeOf(jsonBytes []byte) (JSONType, error) {
+	t, _, err := typeOf(jsonBytes)
+	return t, err
+}
+
+// NumberKindOf is like TypeOf, but it also classifies JSON Number values. If
+// jsonBytes holds any other JSON Data Type then an *UnexpectedTypeError is
+// returned.
+//
+// NumberKindOf never allocates unless an error is returned.
+func NumberKindOf(jsonBytes []byte) (NumberKind, error) {
+	t, kind, err := typeOf(jsonBytes)
+	if err == nil && t != Number {
+		err = &UnexpectedTypeError{Got: t, Allowed: []JSONType{Number},
+			Offset: int64(skipSpace(jsonBytes, 0))}
+	}
+	return kind, err
+}
+
+func typeOf(jsonBytes []byte) (JSONType, NumberKind, error) {
 	if len(jsonBytes) == 0 {
-		return InvalidJSON, ErrEmpty
+		return InvalidJSON, InvalidNumber, ErrEmpty
+	}
+
+	i := skipSpace(jsonBytes, 0)
+	if i == len(jsonBytes) {
+		return InvalidJSON, InvalidNumber, newSyntaxError(jsonBytes, i,
+			ErrEmpty)
 	}
 
 	// See the tip of the next token to avoid decoding expensive values.
-	switch jsonBytes[0] {
+	switch jsonBytes[i] {
 	case '{':
-		return Object, nil
+		return Object, InvalidNumber, nil
 	case '[':
-		return Array, nil
+		return Array, InvalidNumber, nil
 	case '"':
-		return String, nil
+		return String, InvalidNumber, nil
+	}
+
+	// Other small and efficient, byte-optimized comparisons. Invalid values
+	// are reported at the first offending byte.
+	t, kind, end := InvalidJSON, InvalidNumber, i
+	var err error
+	switch c := jsonBytes[i]; {
+	case c == 'n':
+		t = Null
+		end, err = scanLiteral(jsonBytes, i, bNull)
+	case c == 't':
+		t = Boolean
+		end, err = scanLiteral(jsonBytes, i, bTrue)
+	case c == 'f':
+		t = Boolean
+		end, err = scanLiteral(jsonBytes, i, bFalse)
+	case c == '-', '0' <= c && c <= '9':
+		// Should be a number then.
+		t = Number
+		kind, end, err = scanNumber(jsonBytes, i)
+	}
+	if err != nil {
+		t, kind = InvalidJSON, InvalidNumber
+	}
+
+	// Only whitespace is allowed after the value.
+	if t != InvalidJSON {
+		if end = skipSpace(jsonBytes, end); end == len(jsonBytes) {
+			return t, kind, nil
+		}
+	}
+
+	return InvalidJSON, InvalidNumber, newSyntaxError(jsonBytes, end,
+		ErrUnknownType)
+}
+
+// bom is the UTF-8 encoded Byte Order Mark.
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// TypeOfReader is like TypeOf, but it determines the JSON Data Type of the
+// data read from r. It only reads the bytes it needs and it returns a reader
+// that still yields the full stream, including the bytes already read. This
+// way, large streams can be inspected before choosing how to decode them.
+//
+// Leading whitespace and an optional UTF-8 Byte Order Mark are skipped, but
+// they are still yielded by the returned reader. Objects, Arrays and Strings
+// are detected by their first byte. Null, Boolean and Number values follow
+// the same rules as in TypeOf, so they must only be followed by whitespace,
+// but reading stops at the first byte that makes them invalid.
+//
+// If reading from r fails then that error is returned, along with a reader
+// that yields the bytes that were read and then the rest of r.
+func TypeOfReader(r io.Reader) (JSONType, io.Reader, error) {
+	var buf []byte
+	for {
+		if len(buf) == cap(buf) {
+			buf = append(buf, make([]byte, 512)...)[:len(buf)]
+		}
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+
+		atEOF := errors.Is(err, io.EOF)
+		if err != nil && !atEOF {
+			return InvalidJSON, io.MultiReader(bytes.NewReader(buf), r), err
+		}
+
+		t, done, err := typeOfPrefix(buf, atEOF)
+		if done {
+			return t, io.MultiReader(bytes.NewReader(buf), r), err
+		}
+	}
+}
+
+// typeOfPrefix determines the JSON Data Type of the beginning of a stream. It
+// reports whether it had enough data to make a decision, which always happens
+// at the end of the stream.
+func typeOfPrefix(b []byte, atEOF bool) (JSONType, bool, error) {
+	start := 0
+	switch {
+	case bytes.HasPrefix(b, bom):
+		start = len(bom)
+	case !atEOF && bytes.HasPrefix(bom, b):
+		return InvalidJSON, false, nil
+	}
+
+	end := len(b)
+	switch i := skipSpace(b, start); {
+	case i < len(b) && (b[i] == '{' || b[i] == '[' || b[i] == '"'):
+		end = i + 1
+	case !atEOF && !scalarDecided(b, i):
+		return InvalidJSON, false, nil
+	}
+
+	t, err := TypeOf(b[start:end])
+	if e := (*SyntaxError)(nil); errors.As(err, &e) {
+		e.Offset += int64(start)
+	} else if err == ErrEmpty && len(b) > 0 {
+		err = newSyntaxError(b, len(b), ErrEmpty)
+	}
+	return t, true, err
+}
+
+// scalarDecided reports whether b, which holds the beginning of a stream,
+// has enough data to tell if the Null, Boolean or Number value starting at
+// b[i] is valid. That is, if it has the first byte that makes it invalid, or
+// the first byte that is not whitespace after the value.
+func scalarDecided(b []byte, i int) bool {
+	if i == len(b) {
+		return false
+	}
+
+	var end int
+	var err error
+	switch c := b[i]; {
+	case c == 'n':
+		end, err = scanLiteral(b, i, bNull)
+	case c == 't':
+		end, err = scanLiteral(b, i, bTrue)
+	case c == 'f':
+		end, err = scanLiteral(b, i, bFalse)
+	case c == '-', '0' <= c && c <= '9':
+		_, end, err = scanNumber(b, i)
+	default:
+		return true
+	}
+
+	// More data could complete the value, or make a Number longer.
+	if end == len(b) {
+		return false
+	}
+	return err != nil || skipSpace(b, end) < len(b)
+}
+
+// TypeOfStrict is like TypeOf, but it fully validates jsonBytes against the
+// JSON grammar (RFC 8259) in a single pass and without allocating. It returns
+// a *SyntaxError describing the first violation found, which wraps
+// ErrInvalidJSON, ErrUnexpectedEnd or ErrTooDeep.
+//
+// Note that, unlike TypeOf, Objects, Arrays and Strings are validated as a
+// whole. Numbers follow the JSON grammar in both cases, which doesn't allow
+// forms accepted by strconv.ParseFloat like "0x1p-2", "Inf", "NaN", "1_000" or
+// a leading "+".
+func TypeOfStrict(jsonBytes []byte) (JSONType, error) {
+	if len(jsonBytes) == 0 {
+		return InvalidJSON, ErrEmpty
+	}
+
+	i := skipSpace(jsonBytes, 0)
+	if i == len(jsonBytes) {
+		return InvalidJSON, newSyntaxError(jsonBytes, i, ErrEmpty)
 	}
 
-	// Other small and efficient, byte-optimized comparisons.
-	switch 0 {
-	case bytes.Compare(bNull, jsonBytes):
-		return Null, nil
-	case bytes.Compare(bTrue, jsonBytes), bytes.Compare(bFalse, jsonBytes):
-		return Boolean, nil
+	t, i, err := scanValue(jsonBytes, i, 0)
+	if err != nil {
+		return InvalidJSON, err
 	}
 
-	// Should be a number then.
-	if _, err := strconv.ParseFloat(string(jsonBytes), 64); err == nil {
-		return Number, nil
+	if i = skipSpace(jsonBytes, i); i < len(jsonBytes) {
+		return InvalidJSON, newSyntaxError(jsonBytes, i, ErrInvalidJSON)
 	}
 
-	return InvalidJSON, ErrUnknownType
+	return t, nil
 }
@@ -2,7 +2,11 @@ package jsonutils
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 type Response struct {
@@ -248,7 +252,105 @@ var TypeOfTestsRow = []struct {
 		Name:     "Invalid Token",
 		Payload:  json.RawMessage(`!`),
 		JSONType: InvalidJSON,
-		Error:    `unknown type`,
+		Error:    `unknown type: invalid character '!' at offset 0`,
+	}, //*/
+
+	{
+		Name:     "Only whitespace",
+		Payload:  json.RawMessage(" \t\r\n"),
+		JSONType: InvalidJSON,
+		Error:    `empty input at offset 4`,
+	}, //*/
+
+	{
+		Name:     "Object with leading whitespace",
+		Payload:  json.RawMessage(" {}"),
+		JSONType: Object,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Array with leading whitespace",
+		Payload:  json.RawMessage("\r\n\t[]"),
+		JSONType: Array,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Boolean with trailing whitespace",
+		Payload:  json.RawMessage("true\n"),
+		JSONType: Boolean,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Null surrounded by whitespace",
+		Payload:  json.RawMessage(" \t null \r\n"),
+		JSONType: Null,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Number surrounded by whitespace",
+		Payload:  json.RawMessage(" 1.5\n"),
+		JSONType: Number,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Non JSON whitespace",
+		Payload:  json.RawMessage(" \v{}"),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character '\v' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Number overflowing float64",
+		Payload:  json.RawMessage(`1e400`),
+		JSONType: Number,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Hexadecimal number",
+		Payload:  json.RawMessage(`0x10`),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character 'x' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Incomplete number followed by whitespace",
+		Payload:  json.RawMessage(`1. `),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character ' ' at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Two numbers",
+		Payload:  json.RawMessage(`1 2`),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character '2' at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Literal followed by garbage",
+		Payload:  json.RawMessage("false x"),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character 'x' at offset 6`,
+	}, //*/
+
+	{
+		Name:     "Misspelled literal",
+		Payload:  json.RawMessage(`trux`),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character 'x' at offset 3`,
+	}, //*/
+
+	{
+		Name:     "Truncated literal",
+		Payload:  json.RawMessage(` nul!`),
+		JSONType: InvalidJSON,
+		Error:    `unknown type: invalid character '!' at offset 4`,
 	}, //*/
 
 	{
@@ -333,6 +435,26 @@ var TypeOfTestsRow = []struct {
 
 }
 
+func TestTypeOf_Errors(t *testing.T) {
+	_, err := TypeOf([]byte("  ?"))
+
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Expected a *SyntaxError. Got: %#v", err)
+	}
+	if syntaxErr.Offset != 2 || syntaxErr.Char != '?' {
+		t.Fatalf("Unexpected position or character: %#v", syntaxErr)
+	}
+	if !errors.Is(err, ErrUnknownType) {
+		t.Fatalf("Expected error to wrap ErrUnknownType. Got: %v", err)
+	}
+
+	_, err = TypeOf([]byte("  "))
+	if !errors.Is(err, ErrEmpty) {
+		t.Fatalf("Expected error to wrap ErrEmpty. Got: %v", err)
+	}
+}
+
 func TestTypeOf_Raw(t *testing.T) {
 	t.Parallel()
 	for i := range TypeOfTestsRow {
@@ -349,10 +471,470 @@ func TestTypeOf_Raw(t *testing.T) {
 						"\n Got Error: %s", TypeOfTestsRow[i].Error, strErr)
 				}
 				if jType != TypeOfTestsRow[i].JSONType {
-					t.Fatalf("Unexpected JSON Data Type\nWant Type: %d"+
-						"\n Got Type: %d", TypeOfTestsRow[i].JSONType, jType)
+					t.Fatalf("Unexpected JSON Data Type\nWant Type: %v"+
+						"\n Got Type: %v", TypeOfTestsRow[i].JSONType, jType)
+				}
+			}
+		}(i))
+	}
+}
+
+var TypeOfStrictTestsRaw = []struct {
+	Name    string
+	Payload []byte
+	JSONType
+	Error string
+}{
+
+	{
+		Name:     "Empty payload",
+		Payload:  nil,
+		JSONType: InvalidJSON,
+		Error:    ErrEmpty.Error(),
+	}, //*/
+
+	{
+		Name:     "Only whitespace",
+		Payload:  []byte("  "),
+		JSONType: InvalidJSON,
+		Error:    `empty input at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Object",
+		Payload:  []byte(` {"a": [1, -2.5e+3, "xñ", true, false, null, {}] } `),
+		JSONType: Object,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Array",
+		Payload:  []byte(`[[], [[]], {"":""}]`),
+		JSONType: Array,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "String",
+		Payload:  []byte(`"\"\\\/\b\f\n\r\t\uABCD"`),
+		JSONType: String,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Number",
+		Payload:  []byte(`-0.5E-10`),
+		JSONType: Number,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Null",
+		Payload:  []byte("null\n"),
+		JSONType: Null,
+		Error:    ``,
+	}, //*/
+
+	{
+		Name:     "Object with garbage",
+		Payload:  []byte(`{garbage`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'g' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Unterminated object",
+		Payload:  []byte(`{"a":1`),
+		JSONType: InvalidJSON,
+		Error:    `unexpected end of JSON input at offset 6`,
+	}, //*/
+
+	{
+		Name:     "Trailing comma in array",
+		Payload:  []byte(`[1,]`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character ']' at offset 3`,
+	}, //*/
+
+	{
+		Name:     "Missing colon",
+		Payload:  []byte(`{"a" 1}`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '1' at offset 5`,
+	}, //*/
+
+	{
+		Name:     "Non string key",
+		Payload:  []byte(`{1:1}`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '1' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Unterminated string",
+		Payload:  []byte(`"abc`),
+		JSONType: InvalidJSON,
+		Error:    `unexpected end of JSON input at offset 4`,
+	}, //*/
+
+	{
+		Name:     "Invalid escape",
+		Payload:  []byte(`"\x41"`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'x' at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Invalid unicode escape",
+		Payload:  []byte(`"\u12g4"`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'g' at offset 5`,
+	}, //*/
+
+	{
+		Name:     "Control character in string",
+		Payload:  []byte("\"a\tb\""),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '\t' at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Hexadecimal float",
+		Payload:  []byte(`0x1p-2`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'x' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Inf",
+		Payload:  []byte(`Inf`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'I' at offset 0`,
+	}, //*/
+
+	{
+		Name:     "NaN",
+		Payload:  []byte(`NaN`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'N' at offset 0`,
+	}, //*/
+
+	{
+		Name:     "Underscores",
+		Payload:  []byte(`1_000`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '_' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Leading plus",
+		Payload:  []byte(`+1`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '+' at offset 0`,
+	}, //*/
+
+	{
+		Name:     "Leading zero",
+		Payload:  []byte(`01`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '1' at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Missing fraction digits",
+		Payload:  []byte(`1.e3`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character 'e' at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Missing exponent digits",
+		Payload:  []byte(`1e`),
+		JSONType: InvalidJSON,
+		Error:    `unexpected end of JSON input at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Lone minus",
+		Payload:  []byte(`-`),
+		JSONType: InvalidJSON,
+		Error:    `unexpected end of JSON input at offset 1`,
+	}, //*/
+
+	{
+		Name:     "Truncated literal",
+		Payload:  []byte(`tru`),
+		JSONType: InvalidJSON,
+		Error:    `unexpected end of JSON input at offset 3`,
+	}, //*/
+
+	{
+		Name:     "Misspelled literal",
+		Payload:  []byte(`nul1`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '1' at offset 3`,
+	}, //*/
+
+	{
+		Name:     "Two values",
+		Payload:  []byte(`1 2`),
+		JSONType: InvalidJSON,
+		Error:    `invalid JSON: invalid character '2' at offset 2`,
+	}, //*/
+
+	{
+		Name:     "Too deep",
+		Payload:  []byte(strings.Repeat("[", maxNestingDepth+1)),
+		JSONType: InvalidJSON,
+		Error:    `exceeded max nesting depth: invalid character '[' at offset 10000`,
+	}, //*/
+
+	/* Template
+	{
+		Name:     "",
+		Payload:  []byte(``),
+		JSONType: InvalidJSON,
+		Error:    ``,
+	}, //*/
+
+}
+
+func TestTypeOfStrict_Raw(t *testing.T) {
+	t.Parallel()
+	for i := range TypeOfStrictTestsRaw {
+		t.Run(TypeOfStrictTestsRaw[i].Name, func(i int) func(*testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				test := TypeOfStrictTestsRaw[i]
+				var strErr string
+				jType, err := TypeOfStrict(test.Payload)
+				if err != nil {
+					strErr = err.Error()
+				}
+				if strErr != test.Error {
+					t.Fatalf("Unexpected error\nWant Error: %s"+
+						"\n Got Error: %s", test.Error, strErr)
+				}
+				if jType != test.JSONType {
+					t.Fatalf("Unexpected JSON Data Type\nWant Type: %v"+
+						"\n Got Type: %v", test.JSONType, jType)
 				}
 			}
 		}(i))
 	}
 }
+
+func TestTypeOfStrict_Allocs(t *testing.T) {
+	b := []byte(`{"a":[1,-2.5e+3,"xñ",true,false,null,{}]}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := TypeOfStrict(b); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("Want: 0 allocations; Have: %v", allocs)
+	}
+}
+
+func FuzzTypeOfStrict(f *testing.F) {
+	for _, test := range TypeOfStrictTestsRaw {
+		f.Add(test.Payload)
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		jType, err := TypeOfStrict(b)
+		if valid := json.Valid(b); valid != (err == nil) {
+			t.Fatalf("Validity mismatch for %q\nWant valid: %v\nHave Error: %v",
+				b, valid, err)
+		}
+		if err != nil {
+			return
+		}
+		if want, _ := TypeOf(b); want != jType {
+			t.Fatalf("Type mismatch for %q\nWant: %v\nHave: %v", b, want, jType)
+		}
+	})
+}
+
+func TestNumberKindOf(t *testing.T) {
+	for _, test := range []struct {
+		Payload string
+		NumberKind
+		Error error
+	}{
+		{`0`, Integer, nil},
+		{` 18446744073709551615 `, Integer, nil},
+		{`-0`, NegativeInteger, nil},
+		{`-42`, NegativeInteger, nil},
+		{`4.2`, Fraction, nil},
+		{`-4.2`, Fraction, nil},
+		{`42e1`, Exponent, nil},
+		{`-4.2E-1`, Exponent, nil},
+		{`"42"`, InvalidNumber, ErrUnexpectedType},
+		{`true`, InvalidNumber, ErrUnexpectedType},
+		{`4.`, InvalidNumber, ErrUnknownType},
+		{``, InvalidNumber, ErrEmpty},
+	} {
+		kind, err := NumberKindOf([]byte(test.Payload))
+		if !errors.Is(err, test.Error) || (err == nil) != (test.Error == nil) {
+			t.Fatalf("[%s] Want Error: %v; Have Error: %v", test.Payload,
+				test.Error, err)
+		}
+		if kind != test.NumberKind {
+			t.Fatalf("[%s] Want: %d; Have: %d", test.Payload, test.NumberKind,
+				kind)
+		}
+	}
+}
+
+var typeOfBenchmarks = []struct {
+	Name    string
+	Payload []byte
+}{
+	{"Object", []byte(`{"key":"value"}`)},
+	{"String", []byte(`"Lorem ipsum"`)},
+	{"Null", []byte(`null`)},
+	{"Boolean", []byte(` false `)},
+	{"Integer", []byte(`-9223372036854775808`)},
+	{"Float", []byte(`1.797693134862315708145274237317043567981e+308`)},
+}
+
+func TestTypeOf_Allocs(t *testing.T) {
+	for _, bench := range typeOfBenchmarks {
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := TypeOf(bench.Payload); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Fatalf("[%s] Want: 0 allocations; Have: %v", bench.Name, allocs)
+		}
+	}
+}
+
+func BenchmarkTypeOf(b *testing.B) {
+	for _, bench := range typeOfBenchmarks {
+		b.Run(bench.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := TypeOf(bench.Payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNumberKindOf(b *testing.B) {
+	payload := []byte(`-4.2E-1`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NumberKindOf(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTypeOfReader(t *testing.T) {
+	for _, test := range []struct {
+		Name    string
+		Payload string
+		JSONType
+		Error string
+	}{
+		{"Empty", "", InvalidJSON, ErrEmpty.Error()},
+		{"Only whitespace", " \n", InvalidJSON, "empty input at offset 2"},
+		{"Only BOM", "\xEF\xBB\xBF", InvalidJSON, "empty input at offset 3"},
+		{"Object", `{"key":"value"}`, Object, ""},
+		{"Array with BOM and whitespace", "\xEF\xBB\xBF \r\n\t[1,2]", Array, ""},
+		{"String", ` "Lorem ipsum"`, String, ""},
+		{"Null", `null`, Null, ""},
+		{"Boolean followed by whitespace", "true\n", Boolean, ""},
+		{"Number", ` -1.5e3`, Number, ""},
+		{"Number followed by whitespace", "1\n\t", Number, ""},
+		{"Number followed by more values", "1 2", InvalidJSON,
+			"unknown type: invalid character '2' at offset 2"},
+		{"Number with leading zero", "0123", InvalidJSON,
+			"unknown type: invalid character '1' at offset 1"},
+		{"Incomplete number", "-1.", InvalidJSON,
+			"unknown type at offset 3"},
+		{"Invalid literal", "nul!", InvalidJSON,
+			"unknown type: invalid character '!' at offset 3"},
+		{"Literal followed by more bytes", "falsex", InvalidJSON,
+			"unknown type: invalid character 'x' at offset 5"},
+		{"Invalid number", "\xEF\xBB\xBF 1.x", InvalidJSON,
+			"unknown type: invalid character 'x' at offset 6"},
+		{"Partial BOM", "\xEF\xBB{}", InvalidJSON,
+			"unknown type: invalid character 'ï' at offset 0"},
+		{"Unknown", " !", InvalidJSON,
+			"unknown type: invalid character '!' at offset 1"},
+	} {
+		// Read one byte at a time to exercise partial reads.
+		jType, r, err := TypeOfReader(iotest.OneByteReader(
+			strings.NewReader(test.Payload)))
+		var strErr string
+		if err != nil {
+			strErr = err.Error()
+		}
+		if strErr != test.Error {
+			t.Fatalf("[%s] Unexpected error\nWant Error: %s\n Got Error: %s",
+				test.Name, test.Error, strErr)
+		}
+		if jType != test.JSONType {
+			t.Fatalf("[%s] Unexpected JSON Data Type\nWant Type: %v"+
+				"\n Got Type: %v", test.Name, test.JSONType, jType)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil || string(b) != test.Payload {
+			t.Fatalf("[%s] Unexpected stream\nWant: %q\nHave: %q (error: %v)",
+				test.Name, test.Payload, b, err)
+		}
+	}
+}
+
+func TestTypeOfReader_Large(t *testing.T) {
+	// Only the first few bytes of a large value are read.
+	r := &countingReader{r: io.MultiReader(strings.NewReader("  ["),
+		iotest.ErrReader(io.ErrUnexpectedEOF))}
+	jType, _, err := TypeOfReader(r)
+	if err != nil || jType != Array {
+		t.Fatalf("Want: %v; Have: %v (error: %v)", Array, jType, err)
+	}
+	if r.n != 3 {
+		t.Fatalf("Want 3 bytes read; Have: %d", r.n)
+	}
+
+	// Reading stops as soon as a value is known to be invalid.
+	for _, payload := range []string{"nullxxxx", "1.5.5", "-x"} {
+		r = &countingReader{r: iotest.OneByteReader(strings.NewReader(
+			payload + strings.Repeat("x", 4096)))}
+		if _, _, err = TypeOfReader(r); err == nil {
+			t.Fatalf("[%s] Want an error", payload)
+		}
+		if r.n > len(payload) {
+			t.Fatalf("[%s] Want at most %d bytes read; Have: %d", payload,
+				len(payload), r.n)
+		}
+	}
+
+	// Read errors are returned.
+	r = &countingReader{r: io.MultiReader(strings.NewReader("  tr"),
+		iotest.ErrReader(io.ErrUnexpectedEOF))}
+	_, rest, err := TypeOfReader(r)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("Want: %v; Have: %v", io.ErrUnexpectedEOF, err)
+	}
+	if b, _ := io.ReadAll(rest); string(b) != "  tr" {
+		t.Fatalf("Want: %q; Have: %q", "  tr", b)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
@@ -0,0 +1,126 @@
+package jsonutils
+
+import (
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Unquote interprets b as a double-quoted JSON String, returning the string
+// value that b represents.
+//
+// Unlike strconv.Unquote, which implements Go syntax, Unquote follows RFC 8259
+// and matches exactly the behavior of encoding/json: it accepts the "\/"
+// escape, rejects Go-only escapes like "\x41", "\a" or "\U0001F600", and
+// replaces invalid UTF-8 and unpaired UTF-16 surrogates with the Unicode
+// replacement character (U+FFFD).
+func Unquote(b []byte) (string, error) {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return "", ErrInvalidString
+	}
+	s := b[1 : len(b)-1]
+
+	// Fast path: look for the first byte that needs to be escaped or replaced.
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		if c == '\\' || c == '"' || c < ' ' {
+			break
+		}
+		if c < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+		i += size
+	}
+	if i == len(s) {
+		return string(s), nil
+	}
+
+	buf := make([]byte, i, len(s)+2*utf8.UTFMax)
+	copy(buf, s)
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '\\':
+			if i+1 >= len(s) {
+				return "", ErrInvalidString
+			}
+			switch c = s[i+1]; c {
+			case '"', '\\', '/':
+				buf = append(buf, c)
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'u':
+				r := getu4(s[i:])
+				if r < 0 {
+					return "", ErrInvalidString
+				}
+				i += 6
+				if utf16.IsSurrogate(r) {
+					dec := utf16.DecodeRune(r, getu4(s[i:]))
+					if dec != unicode.ReplacementChar {
+						i += 6
+						buf = utf8.AppendRune(buf, dec)
+						continue
+					}
+					r = unicode.ReplacementChar
+				}
+				buf = utf8.AppendRune(buf, r)
+				continue
+			default:
+				return "", ErrInvalidString
+			}
+			i += 2
+
+		case c == '"', c < ' ':
+			return "", ErrInvalidString
+
+		case c < utf8.RuneSelf:
+			buf = append(buf, c)
+			i++
+
+		default:
+			// Invalid UTF-8 is decoded as utf8.RuneError, which is U+FFFD.
+			r, size := utf8.DecodeRune(s[i:])
+			buf = utf8.AppendRune(buf, r)
+			i += size
+		}
+	}
+
+	return string(buf), nil
+}
+
+// getu4 decodes a \uXXXX escape from the beginning of s, returning the hex
+// value, or -1 if s does not start with a valid escape.
+func getu4(s []byte) rune {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return -1
+	}
+	var r rune
+	for _, c := range s[2:6] {
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1
+		}
+		r = r*16 + rune(c)
+	}
+	return r
+}
@@ -0,0 +1,217 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var UnquoteTestsRaw = []struct {
+	Name   string
+	Input  string
+	Output string
+	Error  string
+}{
+
+	{
+		Name:   "Empty string",
+		Input:  `""`,
+		Output: "",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "No escapes",
+		Input:  `"Lorem ipsum ñandú"`,
+		Output: "Lorem ipsum ñandú",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Simple escapes",
+		Input:  `"\"\\\/\b\f\n\r\t"`,
+		Output: "\"\\/\b\f\n\r\t",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Unicode escapes",
+		Input:  `"\u0041\u00f1\u20AC"`,
+		Output: "Añ€",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Surrogate pair",
+		Input:  `"\ud83d\ude00"`,
+		Output: "\U0001F600",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Unpaired high surrogate",
+		Input:  `"\ud83dA"`,
+		Output: "�A",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Unpaired low surrogate",
+		Input:  `"\ude00A"`,
+		Output: "�A",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "High surrogate followed by non surrogate escape",
+		Input:  `"\ud83d\u0041"`,
+		Output: "�A",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Invalid UTF-8",
+		Input:  "\"a\xffb\"",
+		Output: "a�b",
+		Error:  "",
+	}, //*/
+
+	{
+		Name:   "Go-only escape \\x",
+		Input:  `"\x41"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Go-only escape \\a",
+		Input:  `"\a"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Go-only escape \\U",
+		Input:  `"\U0001F600"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Invalid unicode escape",
+		Input:  `"\u00g1"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Control character",
+		Input:  "\"a\nb\"",
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Unescaped quote",
+		Input:  `"a"b"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Escaped closing quote",
+		Input:  `"a\"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Not quoted",
+		Input:  `a`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	{
+		Name:   "Single quote",
+		Input:  `"`,
+		Output: "",
+		Error:  ErrInvalidString.Error(),
+	}, //*/
+
+	/* Template
+	{
+		Name:   "",
+		Input:  `""`,
+		Output: "",
+		Error:  "",
+	}, //*/
+
+}
+
+func TestUnquote_Raw(t *testing.T) {
+	t.Parallel()
+	for i := range UnquoteTestsRaw {
+		t.Run(UnquoteTestsRaw[i].Name, func(i int) func(*testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+				test := UnquoteTestsRaw[i]
+				var strErr string
+				s, err := Unquote([]byte(test.Input))
+				if err != nil {
+					strErr = err.Error()
+				}
+				if strErr != test.Error {
+					t.Fatalf("Unexpected error\nWant Error: %s"+
+						"\n Got Error: %s", test.Error, strErr)
+				}
+				if s != test.Output {
+					t.Fatalf("Unexpected output\nWant: %q\n Got: %q",
+						test.Output, s)
+				}
+			}
+		}(i))
+	}
+}
+
+func FuzzUnquote(f *testing.F) {
+	for _, test := range UnquoteTestsRaw {
+		f.Add([]byte(test.Input))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		// encoding/json allows surrounding whitespace, so only compare inputs
+		// that are delimited by quotes.
+		if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+			return
+		}
+		var want string
+		wantErr := json.Unmarshal(b, &want)
+		have, haveErr := Unquote(b)
+		if (wantErr == nil) != (haveErr == nil) {
+			t.Fatalf("Error mismatch for %q\nWant Error: %v\nHave Error: %v",
+				b, wantErr, haveErr)
+		}
+		if have != want {
+			t.Fatalf("Output mismatch for %q\nWant: %q\nHave: %q", b, want,
+				have)
+		}
+	})
+}
+
+func BenchmarkUnquote(b *testing.B) {
+	for _, bench := range []struct {
+		Name  string
+		Input []byte
+	}{
+		{"No escapes", []byte(`"Lorem ipsum dolor sit amet, consectetur"`)},
+		{"Escapes", []byte(`"Lorem ipsum\n\"dolor\" sit ámet"`)},
+	} {
+		b.Run(bench.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Unquote(bench.Input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}